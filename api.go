@@ -9,6 +9,7 @@ import (
 	"io/ioutil"
 	"log"
 	"net/http"
+	"sync/atomic"
 	"time"
 )
 
@@ -25,8 +26,15 @@ var (
 		"db_error":                errors.New("the database you are trying to access doesn't seem to exist"),
 		"nodev":                   errors.New("invalid interface"),
 	}
+
+	// errNotFound is returned when the endpoint does not exist on this box
+	errNotFound = errors.New("404 Not Found")
 )
 
+// sharedSessionToken publishes the session token of the polling loop
+// so that the event stream can reuse it
+var sharedSessionToken atomic.Value
+
 type ApiResponse interface {
 	Status() (bool, string)
 }
@@ -47,12 +55,13 @@ func getApiData(authInf *authInfo, pr *postRequest, xSessionToken *string, respo
 		return err
 	}
 	req.Header.Add(pr.header, *xSessionToken)
+	sharedSessionToken.Store(*xSessionToken)
 	resp, err := client.Do(req)
 	if err != nil {
 		return err
 	}
 	if resp.StatusCode == 404 {
-		return errors.New(resp.Status)
+		return errNotFound
 	}
 	body, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
@@ -74,6 +83,7 @@ func getApiData(authInf *authInfo, pr *postRequest, xSessionToken *string, respo
 		if err != nil {
 			return err
 		}
+		sharedSessionToken.Store(*xSessionToken)
 	} else if errorCode != "" {
 		if apiErrors[errorCode] == nil {
 			return fmt.Errorf("%s: The API returns an unknown error_code: %s", pr.url, errorCode)
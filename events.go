@@ -0,0 +1,193 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	eventsMinBackoff   = 1 * time.Second
+	eventsMaxBackoff   = 5 * time.Minute
+	eventsPingInterval = 30 * time.Second
+)
+
+var watchedEvents = []string{
+	"lan_host_l3addr_reachable",
+	"lan_host_l3addr_unreachable",
+	"vm_state_changed",
+	"vm_disk_task_done",
+}
+
+// eventHosts remembers the name label of the LAN hosts series created
+// from events, so they can be deleted
+var eventHosts = struct {
+	sync.Mutex
+	names map[string]string
+}{names: make(map[string]string)}
+
+// eventsURL turns the freebox API endpoint into the websocket event stream url
+func eventsURL(endpoint string) string {
+	if strings.HasPrefix(endpoint, "https://") {
+		endpoint = "wss://" + strings.TrimPrefix(endpoint, "https://")
+	} else {
+		endpoint = "ws://" + strings.TrimPrefix(endpoint, "http://")
+	}
+	return endpoint + "api/v8/ws/event"
+}
+
+// watchEvents keeps the event stream open, reconnecting with an
+// exponential backoff when it fails
+func watchEvents(url, header string, limiter *hostLimiter) {
+	backoff := eventsMinBackoff
+	for {
+		start := time.Now()
+		err := readEvents(url, header, limiter)
+		eventsConnectedGauge.Set(0)
+		log.Printf("An error occured with the event stream: %v", err)
+
+		// a connection that lived long enough was healthy, start over
+		if time.Since(start) > eventsMaxBackoff {
+			backoff = eventsMinBackoff
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+		if backoff > eventsMaxBackoff {
+			backoff = eventsMaxBackoff
+		}
+	}
+}
+
+// readEvents registers to the watched events with the session token of the
+// polling loop and handles notifications until the connection fails
+func readEvents(url, header string, limiter *hostLimiter) error {
+	token, _ := sharedSessionToken.Load().(string)
+	if token == "" {
+		return errors.New("no session token yet")
+	}
+
+	h := http.Header{}
+	h.Set(header, token)
+	conn, _, err := websocket.DefaultDialer.Dial(url, h)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	err = conn.WriteJSON(wsRegister{Action: "register", Events: watchedEvents})
+	if err != nil {
+		return err
+	}
+	eventsConnectedGauge.Set(1)
+
+	// the box does not always close dead connections, ping it
+	conn.SetReadDeadline(time.Now().Add(3 * eventsPingInterval))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(3 * eventsPingInterval))
+	})
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		ticker := time.NewTicker(eventsPingInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(10*time.Second))
+			}
+		}
+	}()
+
+	for {
+		_, msg, err := conn.ReadMessage()
+		if err != nil {
+			return err
+		}
+		err = handleEvent(msg, limiter)
+		if err != nil {
+			log.Printf("An error occured with an event: %v", err)
+		}
+	}
+}
+
+// handleEvent updates the metrics from a message of the event stream,
+// LAN hosts follow the admission of the polling loop
+func handleEvent(msg []byte, limiter *hostLimiter) error {
+	event := wsEvent{}
+	err := json.Unmarshal(msg, &event)
+	if err != nil {
+		if debug {
+			log.Println(string(msg))
+		}
+		return err
+	}
+
+	if ok, errorCode := event.Status(); !ok {
+		if apiErrors[errorCode] == nil {
+			return fmt.Errorf("%s: The API returns an unknown error_code: %s", event.Action, errorCode)
+		}
+		return apiErrors[errorCode]
+	}
+	if event.Action != "notification" {
+		return nil
+	}
+
+	eventsCounter.WithLabelValues(event.Source, event.Event).Inc()
+	if event.Source != "lan_host" {
+		return nil
+	}
+
+	host := lanHost{}
+	err = json.Unmarshal(event.Result, &host)
+	if err != nil {
+		return err
+	}
+	host.PrimaryName = hostRelabel.name(host.L2Ident.ID, host.PrimaryName)
+	if !limiter.admittedHost("lan", host.L2Ident.ID, host.PrimaryName) {
+		return nil
+	}
+
+	eventHosts.Lock()
+	defer eventHosts.Unlock()
+	if name, ok := eventHosts.names[host.L2Ident.ID]; ok && name != host.PrimaryName {
+		deleteEventHost(host.L2Ident.ID, name)
+	}
+	eventHosts.names[host.L2Ident.ID] = host.PrimaryName
+
+	switch event.Event {
+	case "l3addr_reachable":
+		lanHostEventsCounter.WithLabelValues(host.PrimaryName, host.L2Ident.ID, "join").Inc()
+	case "l3addr_unreachable":
+		lanHostEventsCounter.WithLabelValues(host.PrimaryName, host.L2Ident.ID, "leave").Inc()
+	}
+	lanHostLastSeenGauges.WithLabelValues(host.PrimaryName, host.L2Ident.ID).SetToCurrentTime()
+
+	return nil
+}
+
+// pruneEventHosts deletes the series of the LAN hosts no longer admitted
+func pruneEventHosts(limiter *hostLimiter) {
+	eventHosts.Lock()
+	defer eventHosts.Unlock()
+	for mac, name := range eventHosts.names {
+		if !limiter.admittedHost("lan", mac, name) {
+			deleteEventHost(mac, name)
+			delete(eventHosts.names, mac)
+		}
+	}
+}
+
+func deleteEventHost(mac, name string) {
+	lanHostEventsCounter.DeleteLabelValues(name, mac, "join")
+	lanHostEventsCounter.DeleteLabelValues(name, mac, "leave")
+	lanHostLastSeenGauges.DeleteLabelValues(name, mac)
+}
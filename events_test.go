@@ -0,0 +1,80 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestEventsURL(t *testing.T) {
+	url := eventsURL("http://mafreebox.freebox.fr/")
+	if url != "ws://mafreebox.freebox.fr/api/v8/ws/event" {
+		t.Error("Expected ws://mafreebox.freebox.fr/api/v8/ws/event, but got", url)
+	}
+
+	url = eventsURL("https://192.168.1.254:443/")
+	if url != "wss://192.168.1.254:443/api/v8/ws/event" {
+		t.Error("Expected wss://192.168.1.254:443/api/v8/ws/event, but got", url)
+	}
+}
+
+func TestHandleEvent(t *testing.T) {
+	msg := []byte(`{"success":true,"action":"notification","source":"lan_host","event":"l3addr_reachable",
+		"result":{"primary_name":"phone","l2ident":{"id":"AA:BB:CC:DD:EE:FF","type":"mac_address"}}}`)
+
+	err := handleEvent(msg, nil)
+	if err != nil {
+		t.Error("Expected no err, but got", err)
+	}
+
+	joins := testutil.ToFloat64(lanHostEventsCounter.WithLabelValues("phone", "AA:BB:CC:DD:EE:FF", "join"))
+	if joins != 1 {
+		t.Error("Expected 1, but got", joins)
+	}
+
+	lastSeen := testutil.ToFloat64(lanHostLastSeenGauges.WithLabelValues("phone", "AA:BB:CC:DD:EE:FF"))
+	if lastSeen == 0 {
+		t.Error("Expected a timestamp, but got", lastSeen)
+	}
+
+	err = handleEvent([]byte(`{"success":false,"action":"register","error_code":"insufficient_rights"}`), nil)
+	if err == nil || err.Error() != "your app permissions does not allow accessing this API" {
+		t.Error("Expected your app permissions does not allow accessing this API, but got", err)
+	}
+}
+
+func TestHandleEventLimits(t *testing.T) {
+	l, err := newHostLimiter("", "", 0, "lan=1", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.admit("lan", "11:22:33:44:55:01", "laptop")
+	l.admit("lan", "11:22:33:44:55:02", "tablet")
+	l.publish()
+
+	for _, mac := range []string{"11:22:33:44:55:01", "11:22:33:44:55:02"} {
+		msg := []byte(`{"success":true,"action":"notification","source":"lan_host","event":"l3addr_reachable",
+			"result":{"primary_name":"host","l2ident":{"id":"` + mac + `","type":"mac_address"}}}`)
+		err = handleEvent(msg, l)
+		if err != nil {
+			t.Error("Expected no err, but got", err)
+		}
+	}
+
+	if lanHostLastSeenGauges.DeleteLabelValues("host", "11:22:33:44:55:02") {
+		t.Error("Expected no series for a host over the limit")
+	}
+
+	lastSeen := testutil.ToFloat64(lanHostLastSeenGauges.WithLabelValues("host", "11:22:33:44:55:01"))
+	if lastSeen == 0 {
+		t.Error("Expected a timestamp, but got", lastSeen)
+	}
+
+	// a host no longer admitted loses its series
+	l.reset()
+	l.publish()
+	pruneEventHosts(l)
+	if lanHostLastSeenGauges.DeleteLabelValues("host", "11:22:33:44:55:01") {
+		t.Error("Expected the series of a host no longer admitted to be deleted")
+	}
+}
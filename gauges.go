@@ -9,6 +9,127 @@ var (
 	// XXX: see https://dev.freebox.fr/sdk/os/ for API documentation
 	// XXX: see https://prometheus.io/docs/practices/naming/ for metric names
 
+	// connection
+	connectionStateGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_connection_state",
+			Help: "WAN connection state, 1 for the current state",
+		},
+		[]string{
+			"state", // going_up|up|going_down|down
+		},
+	)
+
+	connectionInfoGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_connection_info",
+			Help: "WAN connection media, type and public IPs, always 1",
+		},
+		[]string{
+			"media",
+			"type",
+			"ipv4",
+			"ipv6",
+		},
+	)
+
+	connectionBytesGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_connection_bytes_total",
+			Help: "WAN bytes transferred since the connection went up",
+		},
+		[]string{
+			"direction", // up|down
+		},
+	)
+
+	connectionRateGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_connection_rate_bytes",
+			Help: "WAN current rate (in byte/s)",
+		},
+		[]string{
+			"direction", // up|down
+		},
+	)
+
+	connectionBandwidthGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_connection_bandwidth_bits",
+			Help: "WAN available bandwidth (in bit/s)",
+		},
+		[]string{
+			"direction", // up|down
+		},
+	)
+
+	connectionSettingGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_connection_setting_enabled",
+			Help: "WAN connection settings",
+		},
+		[]string{
+			"setting", // remote_access|api_remote_access|ping|wol|adblock
+		},
+	)
+
+	connectionRemoteAccessPortGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_connection_remote_access_port",
+			Help: "Port of the remote access to Freebox OS, https only when available",
+		},
+		[]string{
+			"protocol", // http|https
+		},
+	)
+
+	ddnsStatusGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_ddns_status",
+			Help: "Dynamic DNS status, 1 for the current status",
+		},
+		[]string{
+			"provider", // ovh|dyndns|noip
+			"status",
+		},
+	)
+
+	ddnsLastRefreshGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_ddns_last_refresh_timestamp_seconds",
+			Help: "Time of the last successful dynamic DNS refresh",
+		},
+		[]string{
+			"provider",
+		},
+	)
+
+	ddnsNextRefreshGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_ddns_next_refresh_timestamp_seconds",
+			Help: "Time of the next dynamic DNS refresh",
+		},
+		[]string{
+			"provider",
+		},
+	)
+
+	connectionIPv6EnabledGauge = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "freebox_connection_ipv6_enabled",
+		Help: "IPv6 state",
+	})
+
+	connectionIPv6DelegationGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_connection_ipv6_delegation",
+			Help: "Delegated IPv6 prefixes, always 1",
+		},
+		[]string{
+			"prefix",
+			"next_hop",
+		},
+	)
+
 	// connectionXdsl
 	connectionXdslStatusUptimeGauges = promauto.NewGaugeVec(prometheus.GaugeOpts{
 		Name: "freebox_connection_xdsl_status_uptime_seconds_total",
@@ -113,6 +234,112 @@ var (
 		Name: "freebox_connection_ftth_sfp_tx_pwr_decibels",
 	})
 
+	// connectionLte
+	connectionLteEnabledGauge = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "freebox_connection_lte_enabled",
+		Help: "4G aggregation enabled",
+	})
+
+	connectionLteStateGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_connection_lte_state",
+			Help: "4G aggregation state, 1 for the current state",
+		},
+		[]string{
+			"state",
+		},
+	)
+
+	connectionLteAssociatedGauge = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "freebox_connection_lte_associated",
+		Help: "4G radio associated to a cell",
+	})
+
+	connectionLteBandLabels = []string{
+		"band",
+		"pci",
+	}
+
+	connectionLteBandEnabledGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_connection_lte_band_enabled",
+			Help: "4G band in use",
+		},
+		connectionLteBandLabels,
+	)
+
+	connectionLteBandBandwidthGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_connection_lte_band_bandwidth_mhz",
+			Help: "4G band bandwidth (in MHz)",
+		},
+		connectionLteBandLabels,
+	)
+
+	connectionLteBandRsrpGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_connection_lte_band_rsrp_dbm",
+			Help: "4G reference signal received power (in dBm)",
+		},
+		connectionLteBandLabels,
+	)
+
+	connectionLteBandRsrqGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_connection_lte_band_rsrq_decibels",
+			Help: "4G reference signal received quality (in dB)",
+		},
+		connectionLteBandLabels,
+	)
+
+	connectionLteBandRssiGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_connection_lte_band_rssi_dbm",
+			Help: "4G received signal strength indicator (in dBm)",
+		},
+		connectionLteBandLabels,
+	)
+
+	connectionLteBandSinrGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_connection_lte_band_sinr_decibels",
+			Help: "4G signal to interference plus noise ratio (in dB)",
+		},
+		connectionLteBandLabels,
+	)
+
+	connectionLteTunnelConnectedGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_connection_lte_tunnel_connected",
+			Help: "Aggregation tunnel connected",
+		},
+		[]string{
+			"tunnel", // lte|xdsl
+		},
+	)
+
+	connectionLteTunnelRateGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_connection_lte_tunnel_rate_bytes",
+			Help: "Aggregation tunnel current rate (in byte/s)",
+		},
+		[]string{
+			"tunnel",    // lte|xdsl
+			"direction", // rx|tx
+		},
+	)
+
+	connectionLteTunnelMaxRateGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_connection_lte_tunnel_max_rate_bytes",
+			Help: "Aggregation tunnel maximum rate (in byte/s)",
+		},
+		[]string{
+			"tunnel",    // lte|xdsl
+			"direction", // rx|tx
+		},
+	)
+
 	// RRD dsl [unstable]
 	rateUpGauge = promauto.NewGauge(prometheus.GaugeOpts{
 		Name: "freebox_dsl_up_bytes",
@@ -157,6 +384,51 @@ var (
 		},
 	)
 
+	freeplugInfoGauges = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "freebox_freeplug_info",
+		Help: "freeplug network, role and model, always 1",
+	},
+		[]string{
+			"id",
+			"net_id",
+			"role", // coordinator|station|proxy_coordinator
+			"model",
+			"local",
+		},
+	)
+	freeplugEthSpeedGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "freebox_freeplug_eth_speed_bits",
+		Help: "ethernet link speed (in bits/s)",
+	},
+		[]string{
+			"id",
+		},
+	)
+	freeplugEthFullDuplexGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "freebox_freeplug_eth_full_duplex",
+		Help: "ethernet link is full duplex",
+	},
+		[]string{
+			"id",
+		},
+	)
+	freeplugEthPortUpGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "freebox_freeplug_eth_port_up",
+		Help: "ethernet port is up",
+	},
+		[]string{
+			"id",
+		},
+	)
+	freeplugInactiveGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "freebox_freeplug_inactive_seconds",
+		Help: "time since last activity (in seconds)",
+	},
+		[]string{
+			"id",
+		},
+	)
+
 	// RRD Net [unstable]
 	bwUpGauge = promauto.NewGauge(prometheus.GaugeOpts{
 		Name: "freebox_net_bw_up_bytes",
@@ -197,111 +469,1103 @@ var (
 		},
 	)
 
-	systemTempGauges = promauto.NewGaugeVec(
-		prometheus.GaugeOpts{
-			Name: "freebox_system_temp_celsius",
-			Help: "Temperature sensors reported by system (in °C)",
+	// event stream
+	eventsCounter = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "freebox_events_total",
+			Help: "Events received on the event stream",
+		},
+		[]string{
+			"source", // lan_host|vm
+			"event",
+		},
+	)
+
+	lanHostEventsCounter = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "freebox_lan_host_events_total",
+			Help: "Hosts joining and leaving the LAN, from the event stream",
 		},
 		[]string{
 			"name",
+			"mac",
+			"event", // join|leave
 		},
 	)
 
-	systemFanGauges = promauto.NewGaugeVec(
+	lanHostLastSeenGauges = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
-			Name: "freebox_system_fan_rpm",
-			Help: "Fan speed reported by system (in RPM)",
+			Name: "freebox_lan_host_last_seen_timestamp_seconds",
+			Help: "Time of the last event received for the host",
 		},
 		[]string{
 			"name",
+			"mac",
 		},
 	)
 
-	systemUptimeGauges = promauto.NewGaugeVec(
-		prometheus.GaugeOpts{
-			Name: "freebox_system_uptime_seconds_total",
+	eventsConnectedGauge = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "freebox_events_connected",
+		Help: "Event stream connected",
+	})
+
+	relabelCollapsedCounter = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "freebox_relabel_collapsed_series_total",
+			Help: "Series not exported because dropped labels made them identical to another one",
 		},
 		[]string{
-			"firmware_version",
+			"family",
 		},
 	)
 
-	// wifi
-	wifiLabels = []string{
-		"access_point",
-		"mac",
-		"hostname",
-		"state",
-	}
+	lanHostKnownGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_lan_host_known",
+			Help: "Host listed in the known hosts inventory",
+		},
+		[]string{
+			"mac",
+		},
+	)
 
-	wifiSignalGauges = promauto.NewGaugeVec(
+	lanHostInventoryInfoGauges = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
-			Name: "freebox_wifi_signal_attenuation_db",
-			Help: "Wifi signal attenuation in decibel",
+			Name: "freebox_lan_host_inventory_info",
+			Help: "Known host details from the inventory",
+		},
+		[]string{
+			"mac",
+			"name",
+			"owner",
+			"tags", // comma separated
 		},
-		wifiLabels,
 	)
 
-	wifiInactiveGauges = promauto.NewGaugeVec(
+	lanUnknownHostsGauge = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "freebox_lan_unknown_hosts",
+		Help: "Number of reachable hosts missing from the known hosts inventory",
+	})
+
+	lanHostFirstSeenGauges = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
-			Name: "freebox_wifi_inactive_duration_seconds",
-			Help: "Wifi inactive duration in seconds",
+			Name: "freebox_lan_host_first_seen_timestamp_seconds",
+			Help: "Time the host was first seen on the LAN",
+		},
+		[]string{
+			"mac",
 		},
-		wifiLabels,
 	)
 
-	wifiConnectionDurationGauges = promauto.NewGaugeVec(
+	lanHostsGauges = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
-			Name: "freebox_wifi_connection_duration_seconds",
-			Help: "Wifi connection duration in seconds",
+			Name: "freebox_lan_hosts",
+			Help: "Number of hosts on LAN",
+		},
+		[]string{
+			"reachable",
 		},
-		wifiLabels,
 	)
 
-	wifiRXBytesGauges = promauto.NewGaugeVec(
+	hostsNotExportedGauges = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
-			Name: "freebox_wifi_rx_bytes",
-			Help: "Wifi received data (from station to Freebox) in bytes",
+			Name: "freebox_hosts_not_exported",
+			Help: "Number of hosts without their own series in the last poll",
+		},
+		[]string{
+			"collector", // lan|wifi|vpn|switch
+			"reason",    // filtered|limit
 		},
-		wifiLabels,
 	)
 
-	wifiTXBytesGauges = promauto.NewGaugeVec(
+	systemTempGauges = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
-			Name: "freebox_wifi_tx_bytes",
-			Help: "Wifi transmitted data (from Freebox to station) in bytes",
+			Name: "freebox_system_temp_celsius",
+			Help: "Temperature sensors reported by system (in °C)",
+		},
+		[]string{
+			"name",
 		},
-		wifiLabels,
 	)
 
-	wifiRXRateGauges = promauto.NewGaugeVec(
+	systemFanGauges = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
-			Name: "freebox_wifi_rx_rate",
-			Help: "Wifi reception data rate (from station to Freebox) in bytes/seconds",
+			Name: "freebox_system_fan_rpm",
+			Help: "Fan speed reported by system (in RPM)",
+		},
+		[]string{
+			"name",
 		},
-		wifiLabels,
 	)
 
-	wifiTXRateGauges = promauto.NewGaugeVec(
+	systemUptimeGauges = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
-			Name: "freebox_wifi_tx_rate",
-			Help: "Wifi transmission data rate (from Freebox to station) in bytes/seconds",
+			Name: "freebox_system_uptime_seconds_total",
+		},
+		[]string{
+			"firmware_version",
 		},
-		wifiLabels,
 	)
 
-	// vpn server connections list [unstable]
-	vpnServerConnectionsList = promauto.NewGaugeVec(
+	systemInfoGauges = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
-			Name: "vpn_server_connections_list",
-			Help: "VPN server connections list",
+			Name: "freebox_system_info",
+			Help: "Freebox Server hardware and firmware, always 1",
 		},
 		[]string{
-			"user",
-			"vpn",
-			"src_ip",
-			"local_ip",
-			"name", // rx_bytes|tx_bytes
+			"model",
+			"pretty_name",
+			"board",
+			"serial", // empty unless -system-serial is set
+			"mac",
+			"operator",
+			"wifi_type",
+			"firmware_version",
+		},
+	)
+
+	systemExpansionLabels = []string{
+		"slot",
+		"type",
+		"bundle",
+	}
+
+	systemExpansionPresentGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_system_expansion_present",
+			Help: "Expansion module present in the slot",
+		},
+		systemExpansionLabels,
+	)
+
+	systemExpansionSupportedGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_system_expansion_supported",
+			Help: "Expansion module supported by the firmware",
+		},
+		systemExpansionLabels,
+	)
+
+	systemExpansionProbeDoneGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_system_expansion_probe_done",
+			Help: "Expansion module probe completed",
+		},
+		systemExpansionLabels,
+	)
+
+	// lcd
+	lcdBrightnessGauge = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "freebox_lcd_brightness_percent",
+		Help: "Front panel screen brightness (in %)",
+	})
+
+	lcdLedStripEnabledGauge = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "freebox_lcd_led_strip_enabled",
+		Help: "LED strip enabled",
+	})
+
+	lcdLedStripBrightnessGauge = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "freebox_lcd_led_strip_brightness_percent",
+		Help: "LED strip brightness (in %)",
+	})
+
+	lcdInfoGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_lcd_info",
+			Help: "Front panel screen orientation and LED strip animation, always 1",
+		},
+		[]string{
+			"orientation",
+			"orientation_forced",
+			"hide_wifi_key",
+			"led_strip_animation",
+		},
+	)
+
+	lcdLastChangeGauge = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "freebox_lcd_last_change_timestamp_seconds",
+		Help: "Time the exporter last saw the screen or LED configuration change",
+	})
+
+	// vm
+	vmStatusGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_vm_status",
+			Help: "Virtual machine status, 1 for the current status",
+		},
+		[]string{
+			"name",
+			"status", // stopped|running|starting|stopping
+		},
+	)
+
+	vmVcpusGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_vm_vcpus",
+			Help: "Virtual CPUs allocated to the virtual machine",
+		},
+		[]string{
+			"name",
+		},
+	)
+
+	vmMemoryGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_vm_memory_bytes",
+			Help: "Memory allocated to the virtual machine (in bytes)",
+		},
+		[]string{
+			"name",
+		},
+	)
+
+	vmDiskGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_vm_disk_bytes",
+			Help: "Virtual machine disk size (in bytes)",
+		},
+		[]string{
+			"name",
+			"type", // actual|virtual
+		},
+	)
+
+	vmHostCpusGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_vm_host_cpus",
+			Help: "CPUs available to virtual machines",
+		},
+		[]string{
+			"type", // used|total
+		},
+	)
+
+	vmHostMemoryGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_vm_host_memory_bytes",
+			Help: "Memory available to virtual machines (in bytes)",
+		},
+		[]string{
+			"type", // used|total
+		},
+	)
+
+	// phone
+	phoneNetworkUpGauge = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "freebox_phone_network_up",
+		Help: "Telephony network is up",
+	})
+
+	phoneDectGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_phone_dect",
+			Help: "DECT base settings",
+		},
+		[]string{
+			"setting", // enabled|eco_mode|registration_open|nemo_mode
+		},
+	)
+
+	phoneDectHandsetsGauge = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "freebox_phone_dect_handsets",
+		Help: "Number of DECT handsets registered on the base",
+	})
+
+	// wifi
+	wifiLabels = []string{
+		"access_point",
+		"mac",
+		"hostname",
+	}
+
+	wifiApStationsGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_wifi_ap_stations",
+			Help: "Number of stations connected to the access point",
+		},
+		[]string{
+			"access_point",
+		},
+	)
+
+	wifiApStationBytesGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_wifi_ap_station_bytes",
+			Help: "Sum of the bytes of the stations connected to the access point",
+		},
+		[]string{
+			"access_point",
+			"direction", // rx|tx
+		},
+	)
+
+	wifiApStationRateGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_wifi_ap_station_rate",
+			Help: "Sum of the rates of the stations connected to the access point",
+		},
+		[]string{
+			"access_point",
+			"direction", // rx|tx
+		},
+	)
+
+	wifiSignalGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_wifi_signal_attenuation_db",
+			Help: "Wifi signal attenuation in decibel",
+		},
+		wifiLabels,
+	)
+
+	wifiInactiveGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_wifi_inactive_duration_seconds",
+			Help: "Wifi inactive duration in seconds",
+		},
+		wifiLabels,
+	)
+
+	wifiConnectionDurationGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_wifi_connection_duration_seconds",
+			Help: "Wifi connection duration in seconds",
+		},
+		wifiLabels,
+	)
+
+	wifiRXBytesGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_wifi_rx_bytes",
+			Help: "Wifi received data (from station to Freebox) in bytes",
+		},
+		wifiLabels,
+	)
+
+	wifiTXBytesGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_wifi_tx_bytes",
+			Help: "Wifi transmitted data (from Freebox to station) in bytes",
+		},
+		wifiLabels,
+	)
+
+	wifiRXRateGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_wifi_rx_rate",
+			Help: "Wifi reception data rate (from station to Freebox) in bytes/seconds",
+		},
+		wifiLabels,
+	)
+
+	wifiTXRateGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_wifi_tx_rate",
+			Help: "Wifi transmission data rate (from Freebox to station) in bytes/seconds",
+		},
+		wifiLabels,
+	)
+
+	wifiStationStateGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_wifi_station_state",
+			Help: "Wifi station state, 1 for the current state",
+		},
+		[]string{
+			"access_point",
+			"mac",
+			"hostname",
+			"state", // assoc|auth|authorized
+		},
+	)
+
+	wifiStationInfoGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_wifi_station_info",
+			Help: "Wifi station BSS and standard, always 1",
+		},
+		[]string{
+			"access_point",
+			"mac",
+			"hostname",
+			"bssid",
+			"standard",
+		},
+	)
+
+	wifiStationFlagGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_wifi_station_flag",
+			Help: "Wifi station flags",
+		},
+		[]string{
+			"access_point",
+			"mac",
+			"hostname",
+			"flag", // legacy|ht|vht|he|authorized|wmm|power_save
+		},
+	)
+
+	wifiStationLinkLabels = []string{
+		"access_point",
+		"mac",
+		"hostname",
+		"direction", // rx|tx
+	}
+
+	wifiStationPhyRateGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_wifi_station_phy_rate_bits",
+			Help: "Wifi PHY rate of the last frames (in bits/s)",
+		},
+		wifiStationLinkLabels,
+	)
+
+	wifiStationMcsGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_wifi_station_mcs",
+			Help: "Wifi MCS index of the last frames",
+		},
+		wifiStationLinkLabels,
+	)
+
+	wifiStationNssGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_wifi_station_nss",
+			Help: "Wifi number of spatial streams of the last frames",
+		},
+		wifiStationLinkLabels,
+	)
+
+	wifiStationBandwidthGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_wifi_station_bandwidth_mhz",
+			Help: "Wifi channel bandwidth of the last frames (in MHz)",
+		},
+		wifiStationLinkLabels,
+	)
+
+	wifiEnabledGauge = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "freebox_wifi_enabled",
+		Help: "Wifi global state",
+	})
+
+	wifiMacFilterStateGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_wifi_mac_filter_state",
+			Help: "Wifi MAC filter state, 1 for the current state",
+		},
+		[]string{
+			"state", // disabled|whitelist|blacklist
+		},
+	)
+
+	wifiApStateGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_wifi_ap_state",
+			Help: "Wifi access point radio state, 1 for the current state",
+		},
+		[]string{
+			"access_point",
+			"band",
+			"state",
+		},
+	)
+
+	wifiApChannelGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_wifi_ap_channel",
+			Help: "Wifi access point channel in use",
+		},
+		[]string{
+			"access_point",
+			"band",
+			"type", // primary|secondary
+		},
+	)
+
+	wifiApChannelWidthGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_wifi_ap_channel_width_mhz",
+			Help: "Wifi access point channel width in use (in MHz)",
+		},
+		[]string{
+			"access_point",
+			"band",
+		},
+	)
+
+	wifiApCapabilityGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_wifi_ap_capability",
+			Help: "Wifi access point capabilities per band",
+		},
+		[]string{
+			"access_point",
+			"band",
+			"capability",
+		},
+	)
+
+	wifiBssLabels = []string{
+		"access_point",
+		"bssid",
+		"ssid",
+	}
+
+	wifiBssInfoGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_wifi_bss_info",
+			Help: "Wifi BSS configuration, always 1",
+		},
+		[]string{
+			"access_point",
+			"bssid",
+			"ssid",
+			"encryption",
+			"state",
+			"hidden",
+			"main",
+		},
+	)
+
+	wifiBssEnabledGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_wifi_bss_enabled",
+			Help: "Wifi BSS enabled",
+		},
+		wifiBssLabels,
+	)
+
+	wifiBssStationsGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_wifi_bss_stations",
+			Help: "Number of stations associated to the BSS",
+		},
+		[]string{
+			"access_point",
+			"bssid",
+			"ssid",
+			"type", // all|authorized
+		},
+	)
+
+	// wifi survey
+	wifiChannelLabels = []string{
+		"access_point",
+		"band",
+		"channel",
+	}
+
+	wifiChannelNoiseGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_wifi_channel_noise_dbm",
+			Help: "Noise level measured on the channel (in dBm)",
+		},
+		wifiChannelLabels,
+	)
+
+	wifiChannelBusyGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_wifi_channel_busy_percent",
+			Help: "Percentage of time the channel is busy",
+		},
+		wifiChannelLabels,
+	)
+
+	wifiChannelRxBusyGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_wifi_channel_rx_busy_percent",
+			Help: "Percentage of time the access point is receiving on the channel",
+		},
+		wifiChannelLabels,
+	)
+
+	wifiChannelTxGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_wifi_channel_tx_percent",
+			Help: "Percentage of time the access point is transmitting on the channel",
+		},
+		wifiChannelLabels,
+	)
+
+	wifiNeighborsGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_wifi_neighbors",
+			Help: "Number of neighbor access points seen, before the export limit is applied",
+		},
+		[]string{
+			"access_point",
+		},
+	)
+
+	wifiNeighborSignalGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_wifi_neighbor_signal_dbm",
+			Help: "Signal of a neighbor access point (in dBm)",
+		},
+		[]string{
+			"access_point",
+			"bssid",
+			"ssid",
+			"band",
+			"channel",
+		},
+	)
+
+	// home automation
+	homeNodeStatusGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_home_node_status",
+			Help: "Home node status, 1 for the current status",
+		},
+		[]string{
+			"node",
+			"category",
+			"status",
+		},
+	)
+
+	homeEndpointLabels = []string{
+		"node",
+		"category",
+		"endpoint",
+	}
+
+	homeEndpointValueGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_home_endpoint_value",
+			Help: "Readable numeric and boolean values of home nodes",
+		},
+		homeEndpointLabels,
+	)
+
+	homeEndpointInfoGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_home_endpoint_info",
+			Help: "Readable string values of home nodes, always 1",
+		},
+		[]string{
+			"node",
+			"category",
+			"endpoint",
+			"value",
+		},
+	)
+
+	homeNodeBatteryGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_home_node_battery_percent",
+			Help: "Home node battery level (in %)",
+		},
+		[]string{
+			"node",
+			"category",
+		},
+	)
+
+	homeNodeLastTriggerGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_home_node_last_trigger_timestamp_seconds",
+			Help: "Time the trigger of a home sensor was last seen changing between two polls, triggers shorter than a poll are missed",
+		},
+		[]string{
+			"node",
+			"category",
+		},
+	)
+
+	homeAlarmArmedGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_home_alarm_armed",
+			Help: "Alarm arm state",
+		},
+		[]string{
+			"node",
+		},
+	)
+
+	// camera
+	cameraOnlineGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_camera_online",
+			Help: "Camera reachable by the Freebox",
+		},
+		[]string{
+			"camera",
+		},
+	)
+
+	cameraStreamGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_camera_stream_available",
+			Help: "Camera exposes a video stream",
+		},
+		[]string{
+			"camera",
+		},
+	)
+
+	// pvr
+	pvrProgrammedGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_pvr_programmed_recordings",
+			Help: "Number of scheduled recordings per state",
+		},
+		[]string{
+			"state", // disabled|waiting_start_time|starting|running|finished|failed|start_error|...
+		},
+	)
+
+	pvrFinishedGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_pvr_finished_recordings",
+			Help: "Number of finished recordings per state",
+		},
+		[]string{
+			"state",
+		},
+	)
+
+	pvrDiskGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_pvr_disk_bytes",
+			Help: "PVR disk quota (in bytes)",
+		},
+		[]string{
+			"type", // free|total
+		},
+	)
+
+	// parental control
+	parentalModeGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_parental_profile_mode",
+			Help: "Parental control current mode of the profile, 1 for the current mode",
+		},
+		[]string{
+			"profile",
+			"mode", // allowed|denied|webonly
+		},
+	)
+
+	parentalOverrideGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_parental_profile_override",
+			Help: "Parental control override timer active",
+		},
+		[]string{
+			"profile",
+		},
+	)
+
+	parentalHostsGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_parental_profile_hosts",
+			Help: "Number of hosts attached to the profile",
+		},
+		[]string{
+			"profile",
+		},
+	)
+
+	parentalNextChangeGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_parental_profile_next_change_timestamp_seconds",
+			Help: "Time of the next scheduled mode change of the profile",
+		},
+		[]string{
+			"profile",
+		},
+	)
+
+	// player
+	playerInfoGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_player_info",
+			Help: "Freebox Player model and MAC address, always 1",
+		},
+		[]string{
+			"player",
+			"model",
+			"mac",
+		},
+	)
+
+	playerReachableGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_player_reachable",
+			Help: "Freebox Player reachable by the Server",
+		},
+		[]string{
+			"player",
+		},
+	)
+
+	playerPowerStateGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_player_power_state",
+			Help: "Freebox Player power state, 1 for the current state",
+		},
+		[]string{
+			"player",
+			"state", // running|standby
+		},
+	)
+
+	playerUptimeGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_player_uptime_seconds",
+			Help: "Freebox Player uptime (in seconds)",
+		},
+		[]string{
+			"player",
+		},
+	)
+
+	playerVolumeGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_player_volume",
+			Help: "Freebox Player volume",
+		},
+		[]string{
+			"player",
+		},
+	)
+
+	playerForegroundAppGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_player_foreground_app",
+			Help: "Application running in the foreground of the Freebox Player, always 1",
+		},
+		[]string{
+			"player",
+			"app",
+		},
+	)
+
+	// vpn server connections list [unstable]
+	vpnServerConnectionsList = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "vpn_server_connections_list",
+			Help: "VPN server connections list",
+		},
+		[]string{
+			"user",
+			"vpn",
+			"src_ip",
+			"local_ip",
+			"name", // rx_bytes|tx_bytes
+		},
+	)
+
+	vpnServerSessionLabels = []string{
+		"id",
+		"user",
+		"vpn",
+		"src_ip",
+		"local_ip",
+	}
+
+	vpnServerSessionAuthenticatedGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_vpn_server_session_authenticated",
+			Help: "VPN server session authenticated",
+		},
+		vpnServerSessionLabels,
+	)
+
+	vpnServerSessionConnectedSinceGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_vpn_server_session_connected_since_timestamp_seconds",
+			Help: "Time the VPN server session was authenticated",
+		},
+		vpnServerSessionLabels,
+	)
+
+	vpnServerStateGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_vpn_server_state",
+			Help: "VPN server state, 1 for the current state",
+		},
+		[]string{
+			"server",
+			"protocol", // pptp|openvpn|ipsec|wireguard
+			"state",    // stopped|starting|started|stopping|error
+		},
+	)
+
+	vpnServerSessionsGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_vpn_server_sessions",
+			Help: "Number of active VPN server sessions",
+		},
+		[]string{
+			"server",
+			"protocol",
+			"authenticated",
+		},
+	)
+
+	vpnClientEnabledGauge = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "freebox_vpn_client_enabled",
+		Help: "VPN client enabled",
+	})
+
+	vpnClientStateGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_vpn_client_state",
+			Help: "VPN client state, 1 for the current state",
+		},
+		[]string{
+			"vpn",
+			"protocol",
+			"state", // going_up|up|going_down|down
+		},
+	)
+
+	vpnClientLastUpGauge = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "freebox_vpn_client_last_up_timestamp_seconds",
+		Help: "Time the VPN client tunnel last went up",
+	})
+
+	vpnClientBytesGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_vpn_client_bytes_total",
+			Help: "VPN client tunnel bytes transferred",
+		},
+		[]string{
+			"direction", // up|down
+		},
+	)
+
+	vpnClientRateGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_vpn_client_rate_bytes",
+			Help: "VPN client tunnel current rate (in byte/s)",
+		},
+		[]string{
+			"direction", // up|down
+		},
+	)
+
+	// file sharing services
+	serviceEnabledGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_service_enabled",
+			Help: "File sharing and media service enabled",
+		},
+		[]string{
+			"service", // samba_file|samba_print|afp|ftp|upnpav|airmedia
+		},
+	)
+
+	serviceGuestAccessGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_service_guest_access_enabled",
+			Help: "File sharing service open to anonymous or guest users",
+		},
+		[]string{
+			"service", // afp|ftp
+		},
+	)
+
+	ftpAnonymousWriteGauge = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "freebox_ftp_anonymous_write_enabled",
+		Help: "FTP anonymous users can write",
+	})
+
+	ftpRemoteAccessGauge = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "freebox_ftp_remote_access_enabled",
+		Help: "FTP server reachable from the internet",
+	})
+
+	// port forwarding
+	portForwardingGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_port_forwarding",
+			Help: "Active port redirections from the WAN, always 1",
+		},
+		[]string{
+			"protocol",
+			"wan_port_start",
+			"wan_port_end",
+			"lan_ip",
+			"lan_port",
+			"upnp", // true if created through UPnP IGD
+		},
+	)
+
+	upnpigdRedirectionsGauge = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "freebox_upnpigd_redirections",
+		Help: "Number of active port redirections created through UPnP IGD",
+	})
+
+	upnpigdRedirectionsCreatedCounter = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "freebox_upnpigd_redirections_created_total",
+		Help: "Number of UPnP IGD port redirections seen appearing since the exporter started",
+	})
+
+	dmzEnabledGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_dmz_enabled",
+			Help: "DMZ state",
+		},
+		[]string{
+			"ip",
+		},
+	)
+
+	switchPortLinkUpGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_switch_port_link_up",
+			Help: "Switch port link state",
+		},
+		[]string{
+			"name",
+		},
+	)
+
+	switchPortSpeedGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_switch_port_speed_bits",
+			Help: "Switch port negotiated speed (in bits/s)",
+		},
+		[]string{
+			"name",
+		},
+	)
+
+	switchPortFullDuplexGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_switch_port_full_duplex",
+			Help: "Switch port negotiated full duplex",
+		},
+		[]string{
+			"name",
+		},
+	)
+
+	switchPortMacsGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_switch_port_macs",
+			Help: "Number of MAC addresses learned on the switch port",
+		},
+		[]string{
+			"name",
+		},
+	)
+
+	switchPortMacInfoGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "freebox_switch_port_mac_info",
+			Help: "MAC addresses learned on the switch port, always 1",
+		},
+		[]string{
+			"port",
+			"mac",
+			"hostname",
 		},
 	)
 
@@ -1,5 +1,47 @@
 package main
 
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+)
+
+func getConnection(authInf *authInfo, pr *postRequest, xSessionToken *string) (connection, error) {
+	connectionResp := connection{}
+	err := getApiData(authInf, pr, xSessionToken, &connectionResp, nil)
+	if err != nil {
+		return connection{}, err
+	}
+	return connectionResp, nil
+}
+
+func getConnectionConfig(authInf *authInfo, pr *postRequest, xSessionToken *string) (connectionConfig, error) {
+	connectionConfigResp := connectionConfig{}
+	err := getApiData(authInf, pr, xSessionToken, &connectionConfigResp, nil)
+	if err != nil {
+		return connectionConfig{}, err
+	}
+	return connectionConfigResp, nil
+}
+
+func getConnectionDdnsStatus(authInf *authInfo, pr *postRequest, xSessionToken *string) (connectionDdnsStatus, error) {
+	connectionDdnsStatusResp := connectionDdnsStatus{}
+	err := getApiData(authInf, pr, xSessionToken, &connectionDdnsStatusResp, nil)
+	if err != nil {
+		return connectionDdnsStatus{}, err
+	}
+	return connectionDdnsStatusResp, nil
+}
+
+func getConnectionIPv6Config(authInf *authInfo, pr *postRequest, xSessionToken *string) (connectionIPv6Config, error) {
+	connectionIPv6ConfigResp := connectionIPv6Config{}
+	err := getApiData(authInf, pr, xSessionToken, &connectionIPv6ConfigResp, nil)
+	if err != nil {
+		return connectionIPv6Config{}, err
+	}
+	return connectionIPv6ConfigResp, nil
+}
+
 func getConnectionXdsl(authInf *authInfo, pr *postRequest, xSessionToken *string) (connectionXdsl, error) {
 	connectionXdslResp := connectionXdsl{}
 	err := getApiData(authInf, pr, xSessionToken, &connectionXdslResp, nil)
@@ -18,6 +60,15 @@ func getConnectionFtth(authInf *authInfo, pr *postRequest, xSessionToken *string
 	return connectionFtthResp, nil
 }
 
+func getConnectionLte(authInf *authInfo, pr *postRequest, xSessionToken *string) (connectionLte, error) {
+	connectionLteResp := connectionLte{}
+	err := getApiData(authInf, pr, xSessionToken, &connectionLteResp, nil)
+	if err != nil {
+		return connectionLte{}, err
+	}
+	return connectionLteResp, nil
+}
+
 func getDsl(authInf *authInfo, pr *postRequest, xSessionToken *string) ([]int64, error) {
 	return getRrdData(authInf, pr, xSessionToken, "dsl", []string{"rate_up", "rate_down", "snr_up", "snr_down"})
 }
@@ -92,6 +143,217 @@ func getWifiStations(authInf *authInfo, pr *postRequest, xSessionToken *string)
 	return wifiStationResp, nil
 }
 
+func getWifiBss(authInf *authInfo, pr *postRequest, xSessionToken *string) (wifiBssList, error) {
+	wifiBssResp := wifiBssList{}
+	err := getApiData(authInf, pr, xSessionToken, &wifiBssResp, nil)
+	if err != nil {
+		return wifiBssList{}, err
+	}
+	return wifiBssResp, nil
+}
+
+func getWifiConfig(authInf *authInfo, pr *postRequest, xSessionToken *string) (wifiConfig, error) {
+	wifiConfigResp := wifiConfig{}
+	err := getApiData(authInf, pr, xSessionToken, &wifiConfigResp, nil)
+	if err != nil {
+		return wifiConfig{}, err
+	}
+	return wifiConfigResp, nil
+}
+
+func getWifiChannelUsage(authInf *authInfo, pr *postRequest, xSessionToken *string) (wifiChannelUsages, error) {
+	wifiChannelUsageResp := wifiChannelUsages{}
+	err := getApiData(authInf, pr, xSessionToken, &wifiChannelUsageResp, nil)
+	if err != nil {
+		return wifiChannelUsages{}, err
+	}
+	return wifiChannelUsageResp, nil
+}
+
+// getWifiNeighbors returns the neighbor access points seen by the given
+// access point, strongest signal first
+func getWifiNeighbors(authInf *authInfo, pr *postRequest, xSessionToken *string) (wifiNeighbors, error) {
+	wifiNeighborsResp := wifiNeighbors{}
+	err := getApiData(authInf, pr, xSessionToken, &wifiNeighborsResp, nil)
+	if err != nil {
+		return wifiNeighbors{}, err
+	}
+
+	sort.SliceStable(wifiNeighborsResp.Result, func(i, j int) bool {
+		return wifiNeighborsResp.Result[i].Signal > wifiNeighborsResp.Result[j].Signal
+	})
+	return wifiNeighborsResp, nil
+}
+
+func getHomeNodes(authInf *authInfo, pr *postRequest, xSessionToken *string) (homeNodes, error) {
+	homeNodesResp := homeNodes{}
+	err := getApiData(authInf, pr, xSessionToken, &homeNodesResp, nil)
+	if err != nil {
+		return homeNodes{}, err
+	}
+	return homeNodesResp, nil
+}
+
+func getHomeTileset(authInf *authInfo, pr *postRequest, xSessionToken *string) (homeTileset, error) {
+	homeTilesetResp := homeTileset{}
+	err := getApiData(authInf, pr, xSessionToken, &homeTilesetResp, nil)
+	if err != nil {
+		return homeTileset{}, err
+	}
+	return homeTilesetResp, nil
+}
+
+func getPvrRecords(authInf *authInfo, pr *postRequest, xSessionToken *string) (pvrRecords, error) {
+	pvrRecordsResp := pvrRecords{}
+	err := getApiData(authInf, pr, xSessionToken, &pvrRecordsResp, nil)
+	if err != nil {
+		return pvrRecords{}, err
+	}
+	return pvrRecordsResp, nil
+}
+
+func getPvrConfig(authInf *authInfo, pr *postRequest, xSessionToken *string) (pvrConfig, error) {
+	pvrConfigResp := pvrConfig{}
+	err := getApiData(authInf, pr, xSessionToken, &pvrConfigResp, nil)
+	if err != nil {
+		return pvrConfig{}, err
+	}
+	return pvrConfigResp, nil
+}
+
+func getProfiles(authInf *authInfo, pr *postRequest, xSessionToken *string) (profiles, error) {
+	profilesResp := profiles{}
+	err := getApiData(authInf, pr, xSessionToken, &profilesResp, nil)
+	if err != nil {
+		return profiles{}, err
+	}
+	return profilesResp, nil
+}
+
+func getNetworkControls(authInf *authInfo, pr *postRequest, xSessionToken *string) (networkControls, error) {
+	networkControlsResp := networkControls{}
+	err := getApiData(authInf, pr, xSessionToken, &networkControlsResp, nil)
+	if err != nil {
+		return networkControls{}, err
+	}
+	return networkControlsResp, nil
+}
+
+func getPlayers(authInf *authInfo, pr *postRequest, xSessionToken *string) (players, error) {
+	playersResp := players{}
+	err := getApiData(authInf, pr, xSessionToken, &playersResp, nil)
+	if err != nil {
+		return players{}, err
+	}
+	return playersResp, nil
+}
+
+func getPlayerStatus(authInf *authInfo, pr *postRequest, xSessionToken *string) (playerStatus, error) {
+	playerStatusResp := playerStatus{}
+	err := getApiData(authInf, pr, xSessionToken, &playerStatusResp, nil)
+	if err != nil {
+		return playerStatus{}, err
+	}
+	return playerStatusResp, nil
+}
+
+func getVMs(authInf *authInfo, pr *postRequest, xSessionToken *string) (vms, error) {
+	vmsResp := vms{}
+	err := getApiData(authInf, pr, xSessionToken, &vmsResp, nil)
+	if err != nil {
+		return vms{}, err
+	}
+	return vmsResp, nil
+}
+
+func getVMInfo(authInf *authInfo, pr *postRequest, xSessionToken *string) (vmInfo, error) {
+	vmInfoResp := vmInfo{}
+	err := getApiData(authInf, pr, xSessionToken, &vmInfoResp, nil)
+	if err != nil {
+		return vmInfo{}, err
+	}
+	return vmInfoResp, nil
+}
+
+// getVMDiskInfo gets the size of a VM disk, diskPath is the base64 encoded
+// path returned in the VM configuration
+func getVMDiskInfo(authInf *authInfo, pr *postRequest, xSessionToken *string, diskPath string) (vmDiskInfo, error) {
+	body, err := json.Marshal(vmDiskRequest{DiskPath: diskPath})
+	if err != nil {
+		return vmDiskInfo{}, err
+	}
+
+	vmDiskInfoResp := vmDiskInfo{}
+	err = getApiData(authInf, pr, xSessionToken, &vmDiskInfoResp, bytes.NewReader(body))
+	if err != nil {
+		return vmDiskInfo{}, err
+	}
+	return vmDiskInfoResp, nil
+}
+
+func getSambaConfig(authInf *authInfo, pr *postRequest, xSessionToken *string) (sambaConfig, error) {
+	sambaConfigResp := sambaConfig{}
+	err := getApiData(authInf, pr, xSessionToken, &sambaConfigResp, nil)
+	if err != nil {
+		return sambaConfig{}, err
+	}
+	return sambaConfigResp, nil
+}
+
+func getAfpConfig(authInf *authInfo, pr *postRequest, xSessionToken *string) (afpConfig, error) {
+	afpConfigResp := afpConfig{}
+	err := getApiData(authInf, pr, xSessionToken, &afpConfigResp, nil)
+	if err != nil {
+		return afpConfig{}, err
+	}
+	return afpConfigResp, nil
+}
+
+func getFtpConfig(authInf *authInfo, pr *postRequest, xSessionToken *string) (ftpConfig, error) {
+	ftpConfigResp := ftpConfig{}
+	err := getApiData(authInf, pr, xSessionToken, &ftpConfigResp, nil)
+	if err != nil {
+		return ftpConfig{}, err
+	}
+	return ftpConfigResp, nil
+}
+
+func getServiceConfig(authInf *authInfo, pr *postRequest, xSessionToken *string) (serviceConfig, error) {
+	serviceConfigResp := serviceConfig{}
+	err := getApiData(authInf, pr, xSessionToken, &serviceConfigResp, nil)
+	if err != nil {
+		return serviceConfig{}, err
+	}
+	return serviceConfigResp, nil
+}
+
+func getPhoneConfig(authInf *authInfo, pr *postRequest, xSessionToken *string) (phoneConfig, error) {
+	phoneConfigResp := phoneConfig{}
+	err := getApiData(authInf, pr, xSessionToken, &phoneConfigResp, nil)
+	if err != nil {
+		return phoneConfig{}, err
+	}
+	return phoneConfigResp, nil
+}
+
+func getPhones(authInf *authInfo, pr *postRequest, xSessionToken *string) (phones, error) {
+	phonesResp := phones{}
+	err := getApiData(authInf, pr, xSessionToken, &phonesResp, nil)
+	if err != nil {
+		return phones{}, err
+	}
+	return phonesResp, nil
+}
+
+func getLcdConfig(authInf *authInfo, pr *postRequest, xSessionToken *string) (lcdConfig, error) {
+	lcdConfigResp := lcdConfig{}
+	err := getApiData(authInf, pr, xSessionToken, &lcdConfigResp, nil)
+	if err != nil {
+		return lcdConfig{}, err
+	}
+	return lcdConfigResp, nil
+}
+
 func getVpnServer(authInf *authInfo, pr *postRequest, xSessionToken *string) (vpnServer, error) {
 	vpnServerResp := vpnServer{}
 	err := getApiData(authInf, pr, xSessionToken, &vpnServerResp, nil)
@@ -101,6 +363,51 @@ func getVpnServer(authInf *authInfo, pr *postRequest, xSessionToken *string) (vp
 	return vpnServerResp, nil
 }
 
+func getFwRedirs(authInf *authInfo, pr *postRequest, xSessionToken *string) (fwRedirs, error) {
+	fwRedirsResp := fwRedirs{}
+	err := getApiData(authInf, pr, xSessionToken, &fwRedirsResp, nil)
+	if err != nil {
+		return fwRedirs{}, err
+	}
+	return fwRedirsResp, nil
+}
+
+func getFwDmz(authInf *authInfo, pr *postRequest, xSessionToken *string) (fwDmz, error) {
+	fwDmzResp := fwDmz{}
+	err := getApiData(authInf, pr, xSessionToken, &fwDmzResp, nil)
+	if err != nil {
+		return fwDmz{}, err
+	}
+	return fwDmzResp, nil
+}
+
+func getUpnpigdRedirs(authInf *authInfo, pr *postRequest, xSessionToken *string) (upnpigdRedirs, error) {
+	upnpigdRedirsResp := upnpigdRedirs{}
+	err := getApiData(authInf, pr, xSessionToken, &upnpigdRedirsResp, nil)
+	if err != nil {
+		return upnpigdRedirs{}, err
+	}
+	return upnpigdRedirsResp, nil
+}
+
+func getVpnServerConfig(authInf *authInfo, pr *postRequest, xSessionToken *string) (vpnServerConfig, error) {
+	vpnServerConfigResp := vpnServerConfig{}
+	err := getApiData(authInf, pr, xSessionToken, &vpnServerConfigResp, nil)
+	if err != nil {
+		return vpnServerConfig{}, err
+	}
+	return vpnServerConfigResp, nil
+}
+
+func getVpnClientStatus(authInf *authInfo, pr *postRequest, xSessionToken *string) (vpnClientStatus, error) {
+	vpnClientStatusResp := vpnClientStatus{}
+	err := getApiData(authInf, pr, xSessionToken, &vpnClientStatusResp, nil)
+	if err != nil {
+		return vpnClientStatus{}, err
+	}
+	return vpnClientStatusResp, nil
+}
+
 func getSwitchStatus(authInf *authInfo, pr *postRequest, xSessionToken *string) (switchStatus, error) {
 	switchStatusResp := switchStatus{}
 	err := getApiData(authInf, pr, xSessionToken, &switchStatusResp, nil)
@@ -10,6 +10,9 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
 )
 
 func TestSetFreeboxToken(t *testing.T) {
@@ -445,6 +448,198 @@ func TestGetSwitch(t *testing.T) {
 
 }
 
+func TestDeleteSwitchPortStats(t *testing.T) {
+	packetsLabels := prometheus.Labels{"name": "Ethernet 2", "direction": "tx", "type": "single", "error": "1"}
+	bytesLabels := prometheus.Labels{"name": "Ethernet 2", "direction": "rx", "type": "good"}
+	rateLabels := prometheus.Labels{"name": "Ethernet 2", "direction": "rx"}
+	otherLabels := prometheus.Labels{"name": "Ethernet 1", "direction": "rx"}
+
+	switchPortPacketsGauges.With(packetsLabels).Set(1)
+	switchPortBytesGauges.With(bytesLabels).Set(1)
+	switchPortBytesRateGauges.With(rateLabels).Set(1)
+	switchPortBytesRateGauges.With(otherLabels).Set(1)
+
+	deleteSwitchPortStats("Ethernet 2")
+
+	if switchPortPacketsGauges.Delete(packetsLabels) || switchPortBytesGauges.Delete(bytesLabels) || switchPortBytesRateGauges.Delete(rateLabels) {
+		t.Error("Expected the stats of the port to be deleted")
+	}
+
+	if !switchPortBytesRateGauges.Delete(otherLabels) {
+		t.Error("Expected the stats of other ports to be kept")
+	}
+
+}
+
+func TestGetConnection(t *testing.T) {
+	os.Setenv("FREEBOX_TOKEN", "IOI")
+	defer os.Unsetenv("FREEBOX_TOKEN")
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		myConnection := connection{
+			apiResponse: apiResponse{Success: true},
+		}
+		myConnection.Result.State = "up"
+		myConnection.Result.Media = "ftth"
+		myConnection.Result.IPv4 = "192.0.2.1"
+		myConnection.Result.BytesDown = 12500000000
+		myConnection.Result.BandwidthUp = 700000000
+
+		result, _ := json.Marshal(myConnection)
+		fmt.Fprintln(w, string(result))
+	}))
+	defer ts.Close()
+
+	pr := &postRequest{
+		method: "GET",
+		header: "X-Fbx-App-Auth",
+		url:    ts.URL,
+	}
+
+	ai := &authInfo{}
+	mySessionToken := "foobar"
+
+	connectionStats, err := getConnection(ai, pr, &mySessionToken)
+	if err != nil {
+		t.Error("Expected no err, but got", err)
+	}
+
+	if connectionStats.Result.State != "up" {
+		t.Error("Expected up, but got", connectionStats.Result.State)
+	}
+
+	if connectionStats.Result.Media != "ftth" {
+		t.Error("Expected ftth, but got", connectionStats.Result.Media)
+	}
+
+	if connectionStats.Result.IPv4 != "192.0.2.1" {
+		t.Error("Expected 192.0.2.1, but got", connectionStats.Result.IPv4)
+	}
+
+	if connectionStats.Result.BytesDown != 12500000000 {
+		t.Error("Expected 12500000000, but got", connectionStats.Result.BytesDown)
+	}
+
+	if connectionStats.Result.BandwidthUp != 700000000 {
+		t.Error("Expected 700000000, but got", connectionStats.Result.BandwidthUp)
+	}
+
+}
+
+func TestGetConnectionLte(t *testing.T) {
+	os.Setenv("FREEBOX_TOKEN", "IOI")
+	defer os.Unsetenv("FREEBOX_TOKEN")
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.RequestURI {
+		case "/lte":
+			fmt.Fprintln(w, `{"success":true,"result":{"enabled":true,"state":"connected","radio":{"associated":true,"bands":[{"band":20,"pci":42,"rsrp":-95}]}}}`)
+		case "/nodev":
+			fmt.Fprintln(w, `{"success":false,"error_code":"nodev"}`)
+		case "/rights":
+			fmt.Fprintln(w, `{"success":false,"error_code":"insufficient_rights"}`)
+		case "/garbage":
+			fmt.Fprintln(w, "<html></html>")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer ts.Close()
+
+	ai := &authInfo{}
+	mySessionToken := "foobar"
+
+	pr := &postRequest{
+		method: "GET",
+		header: "X-Fbx-App-Auth",
+		url:    ts.URL + "/lte",
+	}
+	connectionLteStats, err := getConnectionLte(ai, pr, &mySessionToken)
+	if err != nil {
+		t.Error("Expected no err, but got", err)
+	}
+
+	if connectionLteStats.Result.Radio.Bands[0].Rsrp != -95 {
+		t.Error("Expected -95, but got", connectionLteStats.Result.Radio.Bands[0].Rsrp)
+	}
+
+	for path, unsupported := range map[string]bool{"/missing": true, "/nodev": true, "/rights": false, "/garbage": false} {
+		pr.url = ts.URL + path
+		_, err := getConnectionLte(ai, pr, &mySessionToken)
+		if err == nil {
+			t.Error("Expected an error on", path)
+		}
+		if lteUnsupported(err) != unsupported {
+			t.Errorf("Expected %s to be unsupported: %t, but got %v", path, unsupported, err)
+		}
+	}
+
+	if lteUnsupported(nil) {
+		t.Error("Expected no error not to disable LTE")
+	}
+
+}
+
+func TestGetConnectionDdnsStatus(t *testing.T) {
+	os.Setenv("FREEBOX_TOKEN", "IOI")
+	defer os.Unsetenv("FREEBOX_TOKEN")
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.RequestURI {
+		case "/connection/config/":
+			fmt.Fprintln(w, `{"success":true,"result":{"ping":true,"wol":false,"adblock":false,"remote_access":true,"remote_access_port":8080,"remote_access_ip":"203.0.113.1","api_remote_access":true,"https_available":true,"https_port":8443}}`)
+		case "/connection/ddns/ovh/status/":
+			fmt.Fprintln(w, `{"success":true,"result":{"status":"ok","last_refresh":1600000000,"next_refresh":1600003600,"last_error":0}}`)
+		case "/connection/ddns/noip/status/":
+			fmt.Fprintln(w, `{"success":false,"error_code":"invalid_request"}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer ts.Close()
+
+	ai := &authInfo{}
+	mySessionToken := "foobar"
+	pr := &postRequest{
+		method: "GET",
+		header: "X-Fbx-App-Auth",
+	}
+
+	pr.url = ts.URL + "/connection/config/"
+	connectionConfigStats, err := getConnectionConfig(ai, pr, &mySessionToken)
+	if err != nil {
+		t.Error("Expected no err, but got", err)
+	}
+
+	if !connectionConfigStats.Result.RemoteAccess || connectionConfigStats.Result.RemoteAccessPort != 8080 {
+		t.Error("Expected remote access on 8080, but got", connectionConfigStats.Result)
+	}
+
+	if !connectionConfigStats.Result.HTTPSAvailable || connectionConfigStats.Result.HTTPSPort != 8443 {
+		t.Error("Expected remote HTTPS access on 8443, but got", connectionConfigStats.Result)
+	}
+
+	pr.url = ts.URL + "/connection/ddns/ovh/status/"
+	ddnsStats, err := getConnectionDdnsStatus(ai, pr, &mySessionToken)
+	if err != nil {
+		t.Error("Expected no err, but got", err)
+	}
+
+	if ddnsStats.Result.Status != "ok" {
+		t.Error("Expected ok, but got", ddnsStats.Result.Status)
+	}
+
+	if ddnsStats.Result.NextRefresh != 1600003600 {
+		t.Error("Expected 1600003600, but got", ddnsStats.Result.NextRefresh)
+	}
+
+	pr.url = ts.URL + "/connection/ddns/noip/status/"
+	if _, err := getConnectionDdnsStatus(ai, pr, &mySessionToken); err == nil {
+		t.Error("Expected an error on an unconfigured provider")
+	}
+
+}
+
 func TestGetLan(t *testing.T) {
 	os.Setenv("FREEBOX_TOKEN", "IOI")
 	defer os.Unsetenv("FREEBOX_TOKEN")
@@ -517,6 +712,60 @@ func TestGetLan(t *testing.T) {
 
 }
 
+func TestGetFreeplug(t *testing.T) {
+	os.Setenv("FREEBOX_TOKEN", "IOI")
+	defer os.Unsetenv("FREEBOX_TOKEN")
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `{"success":true,"result":[{"id":"F4:CA:E5:1D:46:AE","members":[
+			{"id":"F4:CA:E5:1D:46:AE","local":true,"net_role":"cco","eth_port_status":"up","eth_full_duplex":true,"has_network":true,"eth_speed":1000,"inactive":-1,"net_id":"F4CAE51D46AEXXXXXXXXXXXXXXXX","rx_rate":-1,"tx_rate":-1,"model":"FBX-PLC-1000"},
+			{"id":"14:0C:76:B2:06:59","local":false,"net_role":"sta","eth_port_status":"down","eth_full_duplex":false,"has_network":false,"eth_speed":0,"inactive":4,"net_id":"F4CAE51D46AEXXXXXXXXXXXXXXXX","rx_rate":340,"tx_rate":280,"model":"FBX-PLC-1000"}
+		]}]}`)
+	}))
+	defer ts.Close()
+
+	pr := &postRequest{
+		method: "GET",
+		header: "X-Fbx-App-Auth",
+		url:    ts.URL,
+	}
+
+	ai := &authInfo{}
+	mySessionToken := "foobar"
+
+	freeplugStats, err := getFreeplug(ai, pr, &mySessionToken)
+	if err != nil {
+		t.Error("Expected no err, but got", err)
+	}
+
+	members := freeplugStats.Result[0].Members
+
+	if role := freeplugRole(members[0].NetRole); role != "coordinator" {
+		t.Error("Expected coordinator, but got", role)
+	}
+
+	if role := freeplugRole(members[1].NetRole); role != "station" {
+		t.Error("Expected station, but got", role)
+	}
+
+	if role := freeplugRole("pco"); role != "proxy_coordinator" {
+		t.Error("Expected proxy_coordinator, but got", role)
+	}
+
+	if role := freeplugRole("unknown"); role != "unknown" {
+		t.Error("Expected unknown, but got", role)
+	}
+
+	if members[0].EthSpeed != 1000 || !members[0].EthFullDuplex {
+		t.Error("Expected a 1000 full duplex link, but got", members[0].EthSpeed, members[0].EthFullDuplex)
+	}
+
+	if members[1].Inactive != 4 {
+		t.Error("Expected 4, but got", members[1].Inactive)
+	}
+
+}
+
 func TestGetSystem(t *testing.T) {
 	os.Setenv("FREEBOX_TOKEN", "IOI")
 	defer os.Unsetenv("FREEBOX_TOKEN")
@@ -581,6 +830,167 @@ func TestGetSystem(t *testing.T) {
 
 }
 
+func TestGetSystemV6(t *testing.T) {
+	os.Setenv("FREEBOX_TOKEN", "IOI")
+	defer os.Unsetenv("FREEBOX_TOKEN")
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `{"success":true,"result":{"firmware_version":"4.7.4","mac":"F4:CA:E5:00:00:00","serial":"123456789","board_name":"fbxgw8r",
+			"model_info":{"name":"fbxgw8-r1","pretty_name":"Freebox v8 (r1)","net_operator":"Free","wifi_type":"2d4_5g_5g","has_vm":true,"has_dect":true},
+			"expansions":[{"type":"dsl_lte","present":true,"slot":0,"probe_done":true,"supported":true,"bundle":"dsl-lte"},{"type":"ftth_p2p","present":false,"slot":1,"probe_done":true,"supported":true,"bundle":"ftth"}]}}`)
+	}))
+	defer ts.Close()
+
+	pr := &postRequest{
+		method: "GET",
+		header: "X-Fbx-App-Auth",
+		url:    ts.URL,
+	}
+
+	ai := &authInfo{}
+	mySessionToken := "foobar"
+
+	systemStats, err := getSystemV6(ai, pr, &mySessionToken)
+	if err != nil {
+		t.Error("Expected no err, but got", err)
+	}
+
+	if systemStats.Result.ModelInfo.PrettyName != "Freebox v8 (r1)" {
+		t.Error("Expected Freebox v8 (r1), but got", systemStats.Result.ModelInfo.PrettyName)
+	}
+
+	if !systemStats.Result.ModelInfo.HasVm || !systemStats.Result.ModelInfo.HasDect {
+		t.Error("Expected VM and DECT support")
+	}
+
+	if len(systemStats.Result.Expansions) != 2 {
+		t.Fatal("Expected 2, but got", len(systemStats.Result.Expansions))
+	}
+
+	if expansion := systemStats.Result.Expansions[0]; !expansion.Present || expansion.Bundle != "dsl-lte" {
+		t.Error("Expected a present dsl-lte expansion, but got", expansion)
+	}
+
+	if expansion := systemStats.Result.Expansions[1]; expansion.Present || expansion.Slot != 1 {
+		t.Error("Expected an empty slot 1, but got", expansion)
+	}
+
+}
+
+func TestGetPhones(t *testing.T) {
+	os.Setenv("FREEBOX_TOKEN", "IOI")
+	defer os.Unsetenv("FREEBOX_TOKEN")
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.RequestURI {
+		case "/phone/config/":
+			fmt.Fprintln(w, `{"success":true,"result":{"network_is_up":true,"dect_eco_mode":false,"dect_pin":"1234","dect_ring_pattern":1,"dect_registration":false,"dect_nemo_mode":true,"dect_enabled":true,"dect_ring_on_off":true}}`)
+		case "/phone/":
+			fmt.Fprintln(w, `{"success":true,"result":[{"id":0,"type":"fxs","is_ringing":false},{"id":1,"type":"dect","vendor":"gigaset","is_ringing":false},{"id":2,"type":"dect","vendor":"panasonic","is_ringing":true}]}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer ts.Close()
+
+	ai := &authInfo{}
+	mySessionToken := "foobar"
+
+	pr := &postRequest{
+		method: "GET",
+		header: "X-Fbx-App-Auth",
+		url:    ts.URL + "/phone/config/",
+	}
+	phoneConfigStats, err := getPhoneConfig(ai, pr, &mySessionToken)
+	if err != nil {
+		t.Error("Expected no err, but got", err)
+	}
+
+	if !phoneConfigStats.Result.DectEnabled || !phoneConfigStats.Result.NetworkIsUp {
+		t.Error("Expected DECT enabled and network up, but got", phoneConfigStats.Result)
+	}
+
+	pr.url = ts.URL + "/phone/"
+	phonesStats, err := getPhones(ai, pr, &mySessionToken)
+	if err != nil {
+		t.Error("Expected no err, but got", err)
+	}
+
+	if handsets := phonesStats.dectHandsets(); handsets != 2 {
+		t.Error("Expected 2, but got", handsets)
+	}
+
+}
+
+func TestGetFileSharingConfig(t *testing.T) {
+	os.Setenv("FREEBOX_TOKEN", "IOI")
+	defer os.Unsetenv("FREEBOX_TOKEN")
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.RequestURI {
+		case "/netshare/samba/":
+			fmt.Fprintln(w, `{"success":true,"result":{"file_share_enabled":true,"print_share_enabled":false,"logon_enabled":false,"workgroup":"WORKGROUP"}}`)
+		case "/netshare/afp/":
+			fmt.Fprintln(w, `{"success":true,"result":{"enabled":true,"guest_allow":true}}`)
+		case "/ftp/config/":
+			fmt.Fprintln(w, `{"success":true,"result":{"enabled":true,"allow_anonymous":true,"allow_anonymous_write":true,"allow_remote_access":false,"weak_password":false,"port_ctrl":21}}`)
+		case "/upnpav/config/":
+			fmt.Fprintln(w, `{"success":true,"result":{"enabled":true}}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer ts.Close()
+
+	ai := &authInfo{}
+	mySessionToken := "foobar"
+	pr := &postRequest{
+		method: "GET",
+		header: "X-Fbx-App-Auth",
+	}
+
+	pr.url = ts.URL + "/netshare/samba/"
+	sambaStats, err := getSambaConfig(ai, pr, &mySessionToken)
+	if err != nil {
+		t.Error("Expected no err, but got", err)
+	}
+
+	if !sambaStats.Result.FileShareEnabled || sambaStats.Result.PrintShareEnabled {
+		t.Error("Expected only file sharing enabled, but got", sambaStats.Result)
+	}
+
+	pr.url = ts.URL + "/netshare/afp/"
+	afpStats, err := getAfpConfig(ai, pr, &mySessionToken)
+	if err != nil {
+		t.Error("Expected no err, but got", err)
+	}
+
+	if !afpStats.Result.GuestAllow {
+		t.Error("Expected guest access, but got", afpStats.Result)
+	}
+
+	pr.url = ts.URL + "/ftp/config/"
+	ftpStats, err := getFtpConfig(ai, pr, &mySessionToken)
+	if err != nil {
+		t.Error("Expected no err, but got", err)
+	}
+
+	if !ftpStats.Result.AllowAnonymousWrite || ftpStats.Result.AllowRemoteAccess {
+		t.Error("Expected anonymous write without remote access, but got", ftpStats.Result)
+	}
+
+	pr.url = ts.URL + "/upnpav/config/"
+	serviceStats, err := getServiceConfig(ai, pr, &mySessionToken)
+	if err != nil {
+		t.Error("Expected no err, but got", err)
+	}
+
+	if !serviceStats.Result.Enabled {
+		t.Error("Expected true, but got", serviceStats.Result.Enabled)
+	}
+
+}
+
 func TestGetWifi(t *testing.T) {
 	os.Setenv("FREEBOX_TOKEN", "IOI")
 	defer os.Unsetenv("FREEBOX_TOKEN")
@@ -639,12 +1049,15 @@ func TestGetWifiStations(t *testing.T) {
 			State:              "authorized",
 			Inactive:           60,
 			RXBytes:            500,
-			TXBytes:            2280000000,
+			TXBytes:            10000,
 			ConnectionDuration: 600,
-			TXRate:             4260000000,
+			TXRate:             20,
 			RXRate:             5,
 			Signal:             -20,
+			Bssid:              "00:11:22:33:44:55",
 		}
+		myStation.Flags.Vht = true
+		myStation.LastRx = wifiStationStats{Bitrate: 8667, Mcs: 3, VhtMcs: 9, Nss: 2, Width: "80"}
 		myWifiStations.Result = []wifiStation{myStation}
 
 		result, _ := json.Marshal(myWifiStations)
@@ -706,6 +1119,580 @@ func TestGetWifiStations(t *testing.T) {
 		t.Error("Expected -20, but got", wifiStationsStats.Result[0].Signal)
 	}
 
+	if wifiStationsStats.Result[0].Bssid != "00:11:22:33:44:55" {
+		t.Error("Expected 00:11:22:33:44:55, but got", wifiStationsStats.Result[0].Bssid)
+	}
+
+	if wifiStationsStats.Result[0].LastRx.Nss != 2 {
+		t.Error("Expected 2, but got", wifiStationsStats.Result[0].LastRx.Nss)
+	}
+
+	if mcs := wifiStationsStats.Result[0].mcs(wifiStationsStats.Result[0].LastRx); mcs != 9 {
+		t.Error("Expected 9, but got", mcs)
+	}
+
+}
+
+func TestGetWifiBss(t *testing.T) {
+	os.Setenv("FREEBOX_TOKEN", "IOI")
+	defer os.Unsetenv("FREEBOX_TOKEN")
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		myWifiBss := wifiBssList{
+			apiResponse: apiResponse{Success: true},
+		}
+		myBss := wifiBss{
+			ID:    "AA:BB:CC:DD:EE:FF",
+			PhyID: 1,
+		}
+		myBss.Status.StaCount = 3
+		myBss.Status.AuthorizedStaCount = 2
+		myBss.Config.Enabled = true
+		myBss.Config.Ssid = "freebox"
+		myBss.Config.Encryption = "wpa2_psk_ccmp"
+		myWifiBss.Result = []wifiBss{myBss}
+
+		result, _ := json.Marshal(myWifiBss)
+		fmt.Fprintln(w, string(result))
+	}))
+	defer ts.Close()
+
+	pr := &postRequest{
+		method: "GET",
+		header: "X-Fbx-App-Auth",
+		url:    ts.URL,
+	}
+
+	ai := &authInfo{}
+	mySessionToken := "foobar"
+
+	wifiBssStats, err := getWifiBss(ai, pr, &mySessionToken)
+	if err != nil {
+		t.Error("Expected no err, but got", err)
+	}
+
+	if wifiBssStats.Result[0].PhyID != 1 {
+		t.Error("Expected 1, but got", wifiBssStats.Result[0].PhyID)
+	}
+
+	if wifiBssStats.Result[0].Config.Ssid != "freebox" {
+		t.Error("Expected freebox, but got", wifiBssStats.Result[0].Config.Ssid)
+	}
+
+	if !wifiBssStats.Result[0].Config.Enabled {
+		t.Error("Expected true, but got", wifiBssStats.Result[0].Config.Enabled)
+	}
+
+	if wifiBssStats.Result[0].Status.StaCount != 3 {
+		t.Error("Expected 3, but got", wifiBssStats.Result[0].Status.StaCount)
+	}
+
+	if wifiBssStats.Result[0].Status.AuthorizedStaCount != 2 {
+		t.Error("Expected 2, but got", wifiBssStats.Result[0].Status.AuthorizedStaCount)
+	}
+
+}
+
+func TestGetWifiNeighbors(t *testing.T) {
+	os.Setenv("FREEBOX_TOKEN", "IOI")
+	defer os.Unsetenv("FREEBOX_TOKEN")
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		myNeighbors := wifiNeighbors{
+			apiResponse: apiResponse{Success: true},
+		}
+		myNeighbors.Result = []wifiNeighbor{
+			{Bssid: "00:00:00:00:00:01", Ssid: "far", Channel: 6, Signal: -85},
+			{Bssid: "00:00:00:00:00:02", Ssid: "near", Channel: 11, Signal: -40},
+			{Bssid: "00:00:00:00:00:03", Ssid: "middle", Channel: 1, Signal: -60},
+		}
+
+		result, _ := json.Marshal(myNeighbors)
+		fmt.Fprintln(w, string(result))
+	}))
+	defer ts.Close()
+
+	pr := &postRequest{
+		method: "GET",
+		header: "X-Fbx-App-Auth",
+		url:    ts.URL,
+	}
+
+	ai := &authInfo{}
+	mySessionToken := "foobar"
+
+	neighborsStats, err := getWifiNeighbors(ai, pr, &mySessionToken)
+	if err != nil {
+		t.Error("Expected no err, but got", err)
+	}
+
+	if len(neighborsStats.Result) != 3 {
+		t.Fatal("Expected 3, but got", len(neighborsStats.Result))
+	}
+
+	for i, ssid := range []string{"near", "middle", "far"} {
+		if neighborsStats.Result[i].Ssid != ssid {
+			t.Errorf("Expected %s at position %d, but got %s", ssid, i, neighborsStats.Result[i].Ssid)
+		}
+	}
+
+}
+
+func TestGetHomeTileset(t *testing.T) {
+	os.Setenv("FREEBOX_TOKEN", "IOI")
+	defer os.Unsetenv("FREEBOX_TOKEN")
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `{"success":true,"result":[{"node_id":5,"type":"info","data":[
+			{"ep_id":1,"name":"battery","value":80,"value_type":"int","ui":{"access":"r"}},
+			{"ep_id":2,"name":"trigger","value":true,"value_type":"bool","ui":{"access":"r"}},
+			{"ep_id":3,"name":"state","value":"alarm1_armed","value_type":"string","ui":{"access":"rw"}}
+		]}]}`)
+	}))
+	defer ts.Close()
+
+	pr := &postRequest{
+		method: "GET",
+		header: "X-Fbx-App-Auth",
+		url:    ts.URL,
+	}
+
+	ai := &authInfo{}
+	mySessionToken := "foobar"
+
+	homeTilesetStats, err := getHomeTileset(ai, pr, &mySessionToken)
+	if err != nil {
+		t.Error("Expected no err, but got", err)
+	}
+
+	data := homeTilesetStats.Result[0].Data
+
+	if value, ok := homeValue(data[0]); !ok || value != 80 {
+		t.Error("Expected 80, but got", value)
+	}
+
+	if value, ok := homeValue(data[1]); !ok || value != 1 {
+		t.Error("Expected 1, but got", value)
+	}
+
+	if _, ok := homeValue(data[2]); ok {
+		t.Error("Expected string value not to be converted")
+	}
+
+}
+
+func TestGetPvrRecords(t *testing.T) {
+	os.Setenv("FREEBOX_TOKEN", "IOI")
+	defer os.Unsetenv("FREEBOX_TOKEN")
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		myRecords := pvrRecords{
+			apiResponse: apiResponse{Success: true},
+		}
+		myRecords.Result = []pvrRecord{
+			{ID: 1, State: "waiting_start_time", Name: "news"},
+			{ID: 2, State: "waiting_start_time", Name: "movie"},
+			{ID: 3, State: "running", Name: "match"},
+		}
+
+		result, _ := json.Marshal(myRecords)
+		fmt.Fprintln(w, string(result))
+	}))
+	defer ts.Close()
+
+	pr := &postRequest{
+		method: "GET",
+		header: "X-Fbx-App-Auth",
+		url:    ts.URL,
+	}
+
+	ai := &authInfo{}
+	mySessionToken := "foobar"
+
+	pvrRecordsStats, err := getPvrRecords(ai, pr, &mySessionToken)
+	if err != nil {
+		t.Error("Expected no err, but got", err)
+	}
+
+	counts := pvrRecordsStats.countByState()
+
+	if counts["waiting_start_time"] != 2 {
+		t.Error("Expected 2, but got", counts["waiting_start_time"])
+	}
+
+	if counts["running"] != 1 {
+		t.Error("Expected 1, but got", counts["running"])
+	}
+
+	if len(counts) != 2 {
+		t.Error("Expected 2, but got", len(counts))
+	}
+
+}
+
+func TestGetPvrConfig(t *testing.T) {
+	os.Setenv("FREEBOX_TOKEN", "IOI")
+	defer os.Unsetenv("FREEBOX_TOKEN")
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `{"success":true,"result":{"margin_before":60,"margin_after":120,"media":"Disque dur","free_bytes":107374182400,"total_bytes":214748364800}}`)
+	}))
+	defer ts.Close()
+
+	pr := &postRequest{
+		method: "GET",
+		header: "X-Fbx-App-Auth",
+		url:    ts.URL,
+	}
+
+	ai := &authInfo{}
+	mySessionToken := "foobar"
+
+	pvrConfigStats, err := getPvrConfig(ai, pr, &mySessionToken)
+	if err != nil {
+		t.Error("Expected no err, but got", err)
+	}
+
+	if pvrConfigStats.Result.FreeBytes != 107374182400 {
+		t.Error("Expected 107374182400, but got", pvrConfigStats.Result.FreeBytes)
+	}
+
+	if pvrConfigStats.Result.TotalBytes != 214748364800 {
+		t.Error("Expected 214748364800, but got", pvrConfigStats.Result.TotalBytes)
+	}
+
+}
+
+func TestGetNetworkControls(t *testing.T) {
+	os.Setenv("FREEBOX_TOKEN", "IOI")
+	defer os.Unsetenv("FREEBOX_TOKEN")
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		myControls := networkControls{
+			apiResponse: apiResponse{Success: true},
+		}
+		myControls.Result = []networkControl{
+			{ProfileID: 1, CurrentMode: "denied", Override: true, OverrideUntil: 2000, Macs: []string{"AA:BB:CC:DD:EE:FF"}},
+			{ProfileID: 2, CurrentMode: "allowed", Override: true},
+			{ProfileID: 3, CurrentMode: "webonly", OverrideUntil: 2000},
+		}
+
+		result, _ := json.Marshal(myControls)
+		fmt.Fprintln(w, string(result))
+	}))
+	defer ts.Close()
+
+	pr := &postRequest{
+		method: "GET",
+		header: "X-Fbx-App-Auth",
+		url:    ts.URL,
+	}
+
+	ai := &authInfo{}
+	mySessionToken := "foobar"
+
+	networkControlsStats, err := getNetworkControls(ai, pr, &mySessionToken)
+	if err != nil {
+		t.Error("Expected no err, but got", err)
+	}
+
+	controls := networkControlsStats.Result
+
+	if controls[0].CurrentMode != "denied" {
+		t.Error("Expected denied, but got", controls[0].CurrentMode)
+	}
+
+	if !controls[0].overridden(time.Unix(1000, 0)) {
+		t.Error("Expected the override to run until its end date")
+	}
+
+	if controls[0].overridden(time.Unix(3000, 0)) {
+		t.Error("Expected the override to be expired")
+	}
+
+	if !controls[1].overridden(time.Unix(3000, 0)) {
+		t.Error("Expected an override without end date to be running")
+	}
+
+	if controls[2].overridden(time.Unix(1000, 0)) {
+		t.Error("Expected no override")
+	}
+
+}
+
+func TestGetPlayerStatus(t *testing.T) {
+	os.Setenv("FREEBOX_TOKEN", "IOI")
+	defer os.Unsetenv("FREEBOX_TOKEN")
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.RequestURI {
+		case "/api/v6/player/":
+			myPlayers := players{
+				apiResponse: apiResponse{Success: true},
+			}
+			myPlayers.Result = []player{
+				{ID: 1, DeviceName: "living", Reachable: true, APIAvailable: true},
+				{ID: 2, DeviceName: "bedroom", Reachable: false, APIAvailable: true},
+				{ID: 3, DeviceName: "kitchen", Reachable: true, APIAvailable: false},
+			}
+			result, _ := json.Marshal(myPlayers)
+			fmt.Fprintln(w, string(result))
+		case "/api/v6/player/1/api/v6/status/":
+			fmt.Fprintln(w, `{"success":true,"result":{"power_state":"running","uptime":3600,"volume":42,"foreground_app":{"package":"fr.freebox.tv"}}}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer ts.Close()
+
+	ai := &authInfo{}
+	mySessionToken := "foobar"
+
+	pr := &postRequest{
+		method: "GET",
+		header: "X-Fbx-App-Auth",
+		url:    ts.URL + "/api/v6/player/",
+	}
+	playersStats, err := getPlayers(ai, pr, &mySessionToken)
+	if err != nil {
+		t.Error("Expected no err, but got", err)
+	}
+
+	var available []string
+	for _, p := range playersStats.Result {
+		if p.statusAvailable() {
+			available = append(available, p.DeviceName)
+		}
+	}
+	if !reflect.DeepEqual(available, []string{"living"}) {
+		t.Error("Expected only living to serve its status, but got", available)
+	}
+
+	pr.url = playerStatusURL(ts.URL+"/", playersStats.Result[0].ID)
+	playerStatusStats, err := getPlayerStatus(ai, pr, &mySessionToken)
+	if err != nil {
+		t.Error("Expected no err, but got", err)
+	}
+
+	if playerStatusStats.Result.PowerState != "running" {
+		t.Error("Expected running, but got", playerStatusStats.Result.PowerState)
+	}
+
+	if playerStatusStats.Result.Volume != 42 {
+		t.Error("Expected 42, but got", playerStatusStats.Result.Volume)
+	}
+
+	if playerStatusStats.Result.ForegroundApp.Package != "fr.freebox.tv" {
+		t.Error("Expected fr.freebox.tv, but got", playerStatusStats.Result.ForegroundApp.Package)
+	}
+
+}
+
+func TestGetLcdConfig(t *testing.T) {
+	os.Setenv("FREEBOX_TOKEN", "IOI")
+	defer os.Unsetenv("FREEBOX_TOKEN")
+
+	brightness := 100
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"success":true,"result":{"brightness":%d,"orientation":90,"orientation_forced":false,"hide_wifi_key":true,"led_strip_enabled":true,"led_strip_brightness":50,"led_strip_animation":"breathing"}}`+"\n", brightness)
+	}))
+	defer ts.Close()
+
+	pr := &postRequest{
+		method: "GET",
+		header: "X-Fbx-App-Auth",
+		url:    ts.URL,
+	}
+
+	ai := &authInfo{}
+	mySessionToken := "foobar"
+
+	lcdConfigStats, err := getLcdConfig(ai, pr, &mySessionToken)
+	if err != nil {
+		t.Error("Expected no err, but got", err)
+	}
+
+	if lcdConfigStats.Result.LedStripAnimation != "breathing" {
+		t.Error("Expected breathing, but got", lcdConfigStats.Result.LedStripAnimation)
+	}
+
+	// the last change is detected by comparing two configs
+	sameStats, _ := getLcdConfig(ai, pr, &mySessionToken)
+	if sameStats.Result != lcdConfigStats.Result {
+		t.Error("Expected the same config, but got", sameStats.Result)
+	}
+
+	brightness = 10
+	changedStats, _ := getLcdConfig(ai, pr, &mySessionToken)
+	if changedStats.Result == lcdConfigStats.Result {
+		t.Error("Expected a changed config, but got", changedStats.Result)
+	}
+
+	// older boxes do not have the endpoint
+	ts404 := httptest.NewServer(http.NotFoundHandler())
+	defer ts404.Close()
+	pr.url = ts404.URL
+	_, err = getLcdConfig(ai, pr, &mySessionToken)
+	if err != errNotFound {
+		t.Error("Expected 404 Not Found, but got", err)
+	}
+
+}
+
+func TestGetVMDiskInfo(t *testing.T) {
+	os.Setenv("FREEBOX_TOKEN", "IOI")
+	defer os.Unsetenv("FREEBOX_TOKEN")
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := vmDiskRequest{}
+		json.NewDecoder(r.Body).Decode(&req)
+
+		myDisk := vmDiskInfo{
+			apiResponse: apiResponse{Success: true},
+		}
+		if req.DiskPath == "L0ZyZWVib3gvVk1zL2RlYmlhbi5xY293Mg==" {
+			myDisk.Result.ActualSize = 1073741824
+			myDisk.Result.VirtualSize = 10737418240
+		}
+		result, _ := json.Marshal(myDisk)
+		fmt.Fprintln(w, string(result))
+	}))
+	defer ts.Close()
+
+	pr := &postRequest{
+		method: "POST",
+		header: "X-Fbx-App-Auth",
+		url:    ts.URL,
+	}
+
+	ai := &authInfo{}
+	mySessionToken := "foobar"
+
+	vmDiskStats, err := getVMDiskInfo(ai, pr, &mySessionToken, "L0ZyZWVib3gvVk1zL2RlYmlhbi5xY293Mg==")
+	if err != nil {
+		t.Error("Expected no err, but got", err)
+	}
+
+	if vmDiskStats.Result.ActualSize != 1073741824 {
+		t.Error("Expected 1073741824, but got", vmDiskStats.Result.ActualSize)
+	}
+
+	if vmDiskStats.Result.VirtualSize != 10737418240 {
+		t.Error("Expected 10737418240, but got", vmDiskStats.Result.VirtualSize)
+	}
+
+}
+
+func TestGetUpnpigdRedirs(t *testing.T) {
+	os.Setenv("FREEBOX_TOKEN", "IOI")
+	defer os.Unsetenv("FREEBOX_TOKEN")
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		myRedirs := upnpigdRedirs{
+			apiResponse: apiResponse{Success: true},
+		}
+		myRedirs.Result = []upnpigdRedir{
+			{ID: "0.0.0.0-TCP-8080", Enabled: true, IPProto: "tcp", WanPort: 8080, LanIP: "192.168.1.10", LanPort: 80},
+			{ID: "0.0.0.0-UDP-3478", Enabled: true, IPProto: "udp", WanPort: 3478, LanIP: "192.168.1.11", LanPort: 3478},
+			{ID: "0.0.0.0-TCP-22", Enabled: false, IPProto: "tcp", WanPort: 22, LanIP: "192.168.1.12", LanPort: 22},
+		}
+
+		result, _ := json.Marshal(myRedirs)
+		fmt.Fprintln(w, string(result))
+	}))
+	defer ts.Close()
+
+	pr := &postRequest{
+		method: "GET",
+		header: "X-Fbx-App-Auth",
+		url:    ts.URL,
+	}
+
+	ai := &authInfo{}
+	mySessionToken := "foobar"
+
+	upnpigdRedirStats, err := getUpnpigdRedirs(ai, pr, &mySessionToken)
+	if err != nil {
+		t.Error("Expected no err, but got", err)
+	}
+
+	if upnpigdRedirStats.Result[0].WanPort != 8080 {
+		t.Error("Expected 8080, but got", upnpigdRedirStats.Result[0].WanPort)
+	}
+
+	known, created := upnpigdRedirStats.created(nil)
+	if created != 0 {
+		t.Error("Expected the first poll to only set the baseline, but got", created)
+	}
+
+	if len(known) != 2 {
+		t.Error("Expected 2, but got", len(known))
+	}
+
+	delete(known, "0.0.0.0-UDP-3478")
+	_, created = upnpigdRedirStats.created(known)
+	if created != 1 {
+		t.Error("Expected 1, but got", created)
+	}
+
+}
+
+func TestGetVpnServerConfig(t *testing.T) {
+	os.Setenv("FREEBOX_TOKEN", "IOI")
+	defer os.Unsetenv("FREEBOX_TOKEN")
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.RequestURI {
+		case "/vpn/":
+			fmt.Fprintln(w, `{"success":true,"result":[{"name":"openvpn_routed","type":"openvpn","state":"started","connection_count":2,"auth_connection_count":1},{"name":"pptp","type":"pptp","state":"stopped","connection_count":0,"auth_connection_count":0}]}`)
+		case "/vpn_client/status/":
+			fmt.Fprintln(w, `{"success":true,"result":{"enabled":true,"active_vpn":"vpn_client_1","active_vpn_description":"office","type":"wireguard","state":"up","last_up":1600000000,"last_error":"none","stats":{"rate_up":120,"rate_down":340,"bytes_up":5000,"bytes_down":9000}}}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer ts.Close()
+
+	ai := &authInfo{}
+	mySessionToken := "foobar"
+
+	pr := &postRequest{
+		method: "GET",
+		header: "X-Fbx-App-Auth",
+		url:    ts.URL + "/vpn/",
+	}
+	vpnServerConfigStats, err := getVpnServerConfig(ai, pr, &mySessionToken)
+	if err != nil {
+		t.Error("Expected no err, but got", err)
+	}
+
+	if len(vpnServerConfigStats.Result) != 2 {
+		t.Fatal("Expected 2, but got", len(vpnServerConfigStats.Result))
+	}
+
+	if server := vpnServerConfigStats.Result[0]; server.ConnectionCount != 2 || server.AuthConnectionCount != 1 {
+		t.Error("Expected 2 connections with 1 authenticated, but got", server)
+	}
+
+	if server := vpnServerConfigStats.Result[1]; server.Type != "pptp" || server.State != "stopped" {
+		t.Error("Expected a stopped pptp server, but got", server)
+	}
+
+	pr.url = ts.URL + "/vpn_client/status/"
+	vpnClientStats, err := getVpnClientStatus(ai, pr, &mySessionToken)
+	if err != nil {
+		t.Error("Expected no err, but got", err)
+	}
+
+	if vpnClientStats.Result.State != "up" || vpnClientStats.Result.LastUp != 1600000000 {
+		t.Error("Expected up since 1600000000, but got", vpnClientStats.Result.State, vpnClientStats.Result.LastUp)
+	}
+
+	stats := vpnClientStats.Result.Stats
+	if stats.RateUp != 120 || stats.RateDown != 340 || stats.BytesUp != 5000 || stats.BytesDown != 9000 {
+		t.Error("Expected 120/340 rates and 5000/9000 bytes, but got", stats)
+	}
+
 }
 
 func Test_getNet(t *testing.T) {
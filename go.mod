@@ -3,7 +3,12 @@ module freebox_exporter
 go 1.14
 
 require (
-	github.com/golang/protobuf v1.2.1-0.20190109072247-347cf4a86c1c // indirect
+	github.com/golang/protobuf v1.2.1-0.20190109072247-347cf4a86c1c
+	github.com/gorilla/websocket v1.4.2
 	github.com/iancoleman/strcase v0.0.0-20191112232945-16388991a334
 	github.com/prometheus/client_golang v0.9.2
+	github.com/prometheus/client_model v0.0.0-20180712105110-5c3871d89910
+	github.com/prometheus/common v0.0.0-20181126121408-4724e9255275
+	golang.org/x/crypto v0.0.0-20200622213623-75b288015ac9
+	gopkg.in/yaml.v2 v2.3.0
 )
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+)
+
+// inventoryHost describes a known device of the network
+type inventoryHost struct {
+	Owner string   `json:"owner,omitempty"`
+	Name  string   `json:"name,omitempty"`
+	Tags  []string `json:"tags,omitempty"`
+}
+
+// inventory maps MAC addresses to known devices and remembers when each
+// MAC address was first seen on the network
+type inventory struct {
+	Hosts map[string]inventoryHost `json:"hosts"`
+
+	firstSeen     map[string]int64
+	stateLocation string
+}
+
+// loadInventory reads the known hosts file and the first seen state,
+// a missing state file is created on the first new host
+func loadInventory(location, stateLocation string) (*inventory, error) {
+	data, err := ioutil.ReadFile(location)
+	if err != nil {
+		return nil, err
+	}
+
+	inv := &inventory{}
+	err = json.Unmarshal(data, inv)
+	if err != nil {
+		return nil, err
+	}
+
+	hosts := make(map[string]inventoryHost)
+	for mac, host := range inv.Hosts {
+		hosts[strings.ToUpper(mac)] = host
+	}
+	inv.Hosts = hosts
+
+	inv.firstSeen = make(map[string]int64)
+	inv.stateLocation = stateLocation
+	data, err = ioutil.ReadFile(stateLocation)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if err == nil {
+		err = json.Unmarshal(data, &inv.firstSeen)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return inv, nil
+}
+
+// lookup returns the known host matching the MAC address
+func (inv *inventory) lookup(mac string) (inventoryHost, bool) {
+	host, ok := inv.Hosts[strings.ToUpper(mac)]
+	return host, ok
+}
+
+// seen returns when the MAC address was first seen, recording it now
+// if it is new
+func (inv *inventory) seen(mac string, now time.Time) (int64, error) {
+	mac = strings.ToUpper(mac)
+	if firstSeen, ok := inv.firstSeen[mac]; ok {
+		return firstSeen, nil
+	}
+
+	inv.firstSeen[mac] = now.Unix()
+	return inv.firstSeen[mac], inv.save()
+}
+
+// save persists the first seen state
+func (inv *inventory) save() error {
+	data, err := json.Marshal(inv.firstSeen)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(inv.stateLocation, data, 0600)
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestInventory(t *testing.T) {
+	dir, err := ioutil.TempDir("", "inventory")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	location := filepath.Join(dir, "inventory.json")
+	stateLocation := filepath.Join(dir, "first_seen.json")
+	err = ioutil.WriteFile(location, []byte(`{"hosts": {"aa:bb:cc:dd:ee:ff": {"owner": "alice", "name": "laptop", "tags": ["work"]}}}`), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	inv, err := loadInventory(location, stateLocation)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	host, known := inv.lookup("AA:BB:CC:DD:EE:FF")
+	if !known {
+		t.Error("Expected AA:BB:CC:DD:EE:FF to be known")
+	}
+	if host.Owner != "alice" {
+		t.Error("Expected alice, but got", host.Owner)
+	}
+	if strings.Join(host.Tags, ",") != "work" {
+		t.Error("Expected work, but got", host.Tags)
+	}
+	if _, known := inv.lookup("11:22:33:44:55:66"); known {
+		t.Error("Expected 11:22:33:44:55:66 to be unknown")
+	}
+
+	firstSeen, err := inv.seen("11:22:33:44:55:66", time.Unix(1000, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if firstSeen != 1000 {
+		t.Error("Expected 1000, but got", firstSeen)
+	}
+
+	inv, err = loadInventory(location, stateLocation)
+	if err != nil {
+		t.Fatal(err)
+	}
+	firstSeen, _ = inv.seen("11:22:33:44:55:66", time.Unix(2000, 0))
+	if firstSeen != 1000 {
+		t.Error("Expected 1000, but got", firstSeen)
+	}
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// hostCollectors are the collectors exporting one series per host
+var hostCollectors = []string{"lan", "wifi", "vpn", "switch"}
+
+// hostReasons are why a host does not get its own series
+var hostReasons = []string{"filtered", "limit"}
+
+// hostLimiter decides which hosts get their own series, hosts are
+// matched on their MAC address or hostname
+type hostLimiter struct {
+	allow     map[string]bool
+	deny      map[string]bool
+	limit     int
+	limits    map[string]int
+	aggregate map[string]bool
+
+	// state of the running poll, only used by the polling loop
+	counts      map[string]int
+	total       int
+	notExported map[string]map[string]int
+	current     map[string]map[string]bool
+
+	// hosts admitted by the last poll, read by the event stream
+	mu       sync.RWMutex
+	admitted map[string]map[string]bool
+}
+
+// newHostLimiter parses the comma separated allow and deny lists,
+// the collector=limit pairs and the aggregated collectors
+func newHostLimiter(allow, deny string, limit int, limits, aggregate string) (*hostLimiter, error) {
+	l := &hostLimiter{
+		allow:     splitHosts(allow),
+		deny:      splitHosts(deny),
+		limit:     limit,
+		limits:    make(map[string]int),
+		aggregate: make(map[string]bool),
+		admitted:  make(map[string]map[string]bool),
+	}
+	l.reset()
+
+	for _, pair := range strings.Split(limits, ",") {
+		if pair == "" {
+			continue
+		}
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 || !isHostCollector(parts[0]) {
+			return nil, fmt.Errorf("invalid collector limit %q", pair)
+		}
+		n, err := strconv.Atoi(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid collector limit %q", pair)
+		}
+		l.limits[parts[0]] = n
+	}
+
+	for _, collector := range strings.Split(aggregate, ",") {
+		if collector == "" {
+			continue
+		}
+		if !isHostCollector(collector) {
+			return nil, fmt.Errorf("invalid aggregated collector %q", collector)
+		}
+		l.aggregate[collector] = true
+	}
+
+	return l, nil
+}
+
+func splitHosts(list string) map[string]bool {
+	hosts := make(map[string]bool)
+	for _, host := range strings.Split(list, ",") {
+		if host = strings.TrimSpace(host); host != "" {
+			hosts[strings.ToLower(host)] = true
+		}
+	}
+	return hosts
+}
+
+func isHostCollector(collector string) bool {
+	for _, c := range hostCollectors {
+		if c == collector {
+			return true
+		}
+	}
+	return false
+}
+
+// reset starts a new polling loop
+func (l *hostLimiter) reset() {
+	l.counts = make(map[string]int)
+	l.total = 0
+	l.notExported = make(map[string]map[string]int)
+	l.current = make(map[string]map[string]bool)
+	for _, collector := range hostCollectors {
+		l.notExported[collector] = make(map[string]int)
+		l.current[collector] = make(map[string]bool)
+	}
+}
+
+// hostKey identifies a host, VPN sessions only have a user
+func hostKey(mac, hostname string) string {
+	if mac != "" {
+		return strings.ToLower(mac)
+	}
+	return strings.ToLower(hostname)
+}
+
+// filtered tells if the host is left out by the allow and deny lists
+func (l *hostLimiter) filtered(mac, hostname string) bool {
+	mac, hostname = strings.ToLower(mac), strings.ToLower(hostname)
+	return l.deny[mac] || l.deny[hostname] || (len(l.allow) > 0 && !l.allow[mac] && !l.allow[hostname])
+}
+
+// limited tells if the collector has a host limit
+func (l *hostLimiter) limited(collector string) bool {
+	_, ok := l.limits[collector]
+	return ok || l.limit > 0
+}
+
+// admit returns whether the host gets its own series in the collector
+func (l *hostLimiter) admit(collector, mac, hostname string) bool {
+	if l.aggregate[collector] {
+		return false
+	}
+
+	if l.filtered(mac, hostname) {
+		l.notExported[collector]["filtered"]++
+		return false
+	}
+
+	if limit, ok := l.limits[collector]; (ok && l.counts[collector] >= limit) || (l.limit > 0 && l.total >= l.limit) {
+		l.notExported[collector]["limit"]++
+		return false
+	}
+
+	l.counts[collector]++
+	l.total++
+	l.current[collector][hostKey(mac, hostname)] = true
+	return true
+}
+
+// publish ends the polling loop, exporting the hosts left out and
+// keeping the admitted hosts for the event stream
+func (l *hostLimiter) publish() {
+	for _, collector := range hostCollectors {
+		for _, reason := range hostReasons {
+			hostsNotExportedGauges.WithLabelValues(collector, reason).Set(float64(l.notExported[collector][reason]))
+		}
+	}
+
+	l.mu.Lock()
+	l.admitted = l.current
+	l.mu.Unlock()
+}
+
+// admittedHost returns whether the host got its own series in the
+// collector on the last poll, limited collectors admit no host before the
+// first poll. It is safe to call from other goroutines, a nil limiter
+// admits all hosts.
+func (l *hostLimiter) admittedHost(collector, mac, hostname string) bool {
+	if l == nil {
+		return true
+	}
+	if l.aggregate[collector] || l.filtered(mac, hostname) {
+		return false
+	}
+	if !l.limited(collector) {
+		return true
+	}
+
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.admitted[collector][hostKey(mac, hostname)]
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestHostLimiter(t *testing.T) {
+	l, err := newHostLimiter("", "aa:bb:cc:dd:ee:ff,guest-phone", 3, "wifi=1", "vpn")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l.reset()
+	if l.admit("lan", "AA:BB:CC:DD:EE:FF", "laptop") {
+		t.Error("Expected denied MAC to be filtered")
+	}
+	if l.admit("lan", "11:22:33:44:55:66", "Guest-Phone") {
+		t.Error("Expected denied hostname to be filtered")
+	}
+	if l.admit("vpn", "", "alice") {
+		t.Error("Expected aggregated collector to be filtered")
+	}
+	if !l.admit("wifi", "11:22:33:44:55:01", "phone") {
+		t.Error("Expected first wifi station to be admitted")
+	}
+	if l.admit("wifi", "11:22:33:44:55:02", "tablet") {
+		t.Error("Expected second wifi station to hit the collector limit")
+	}
+	if !l.admit("lan", "11:22:33:44:55:01", "phone") || !l.admit("lan", "11:22:33:44:55:02", "tablet") {
+		t.Error("Expected LAN hosts to be admitted")
+	}
+	if l.admit("lan", "11:22:33:44:55:03", "tv") {
+		t.Error("Expected fourth host to hit the global limit")
+	}
+
+	if l.admittedHost("lan", "11:22:33:44:55:01", "phone") {
+		t.Error("Expected no host admitted before the poll is published")
+	}
+	l.publish()
+
+	if !l.admittedHost("lan", "11:22:33:44:55:01", "phone") || l.admittedHost("lan", "11:22:33:44:55:03", "tv") {
+		t.Error("Expected only the hosts admitted by the last poll")
+	}
+	if l.admittedHost("vpn", "", "alice") {
+		t.Error("Expected aggregated collector not to admit hosts")
+	}
+
+	if value := testutil.ToFloat64(hostsNotExportedGauges.WithLabelValues("lan", "filtered")); value != 2 {
+		t.Error("Expected 2, but got", value)
+	}
+	if value := testutil.ToFloat64(hostsNotExportedGauges.WithLabelValues("lan", "limit")); value != 1 {
+		t.Error("Expected 1, but got", value)
+	}
+
+	l.reset()
+	if !l.admit("lan", "11:22:33:44:55:03", "tv") {
+		t.Error("Expected limits to be reset")
+	}
+	l.publish()
+
+	if value := testutil.ToFloat64(hostsNotExportedGauges.WithLabelValues("lan", "limit")); value != 0 {
+		t.Error("Expected 0, but got", value)
+	}
+
+	l, err = newHostLimiter("phone", "", 0, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !l.admit("wifi", "11:22:33:44:55:01", "phone") || l.admit("wifi", "11:22:33:44:55:02", "tablet") {
+		t.Error("Expected only allowed hosts to be admitted")
+	}
+
+	if !l.admittedHost("lan", "11:22:33:44:55:09", "phone") {
+		t.Error("Expected allowed hosts to be admitted without limits")
+	}
+
+	var nilLimiter *hostLimiter
+	if !nilLimiter.admittedHost("lan", "11:22:33:44:55:02", "tablet") {
+		t.Error("Expected a nil limiter to admit all hosts")
+	}
+
+	if _, err := newHostLimiter("", "", 0, "dhcp=10", ""); err == nil {
+		t.Error("Expected an error on an unknown collector")
+	}
+}
@@ -17,11 +17,35 @@ import (
 )
 
 var (
-	mafreebox string
-	listen    string
-	debug     bool
-	fiber     bool
-	v6        bool
+	mafreebox          string
+	listen             string
+	debug              bool
+	fiber              bool
+	v6                 bool
+	wifiSurvey         bool
+	wifiSurveyInterval time.Duration
+	wifiNeighborsLimit int
+	home               bool
+	camera             bool
+	pvr                bool
+	parental           bool
+	freeboxPlayer      bool
+	systemSerial       bool
+	switchMacInfo      bool
+	ddnsProviders      string
+	events             bool
+	webhookURL         string
+	webhookDebounce    time.Duration
+	webhookEvents      string
+	inventoryFile      string
+	inventoryState     string
+	relabelFile        string
+	hostAllow          string
+	hostDeny           string
+	hostLimit          int
+	collectorLimits    string
+	aggregateHosts     string
+	webConfigFile      string
 )
 
 func init() {
@@ -30,6 +54,30 @@ func init() {
 	flag.BoolVar(&debug, "debug", false, "Debug mode")
 	flag.BoolVar(&fiber, "fiber", false, "Turn on if you're using a fiber Freebox")
 	flag.BoolVar(&v6, "v6", false, "Use v6+ system API endpoint")
+	flag.StringVar(&inventoryFile, "inventory", "", "JSON file of known hosts by MAC address (empty to disable)")
+	flag.StringVar(&inventoryState, "inventory-state", os.Getenv("HOME")+"/.freebox_first_seen", "File where the first time each host was seen is kept")
+	flag.StringVar(&relabelFile, "relabel-config", "", "JSON file of host relabel rules (empty to disable)")
+	flag.StringVar(&hostAllow, "host-allow", "", "Comma separated MAC addresses or hostnames exported per host (empty for all)")
+	flag.StringVar(&hostDeny, "host-deny", "", "Comma separated MAC addresses or hostnames never exported per host")
+	flag.IntVar(&hostLimit, "host-limit", 0, "Maximum number of per-host series exported across all collectors on each poll (0 for no limit)")
+	flag.StringVar(&collectorLimits, "collector-host-limits", "", "Comma separated collector=limit pairs among lan, wifi, vpn and switch")
+	flag.StringVar(&aggregateHosts, "aggregate-hosts", "", "Comma separated collectors among lan, wifi, vpn and switch only exporting aggregates")
+	flag.StringVar(&webConfigFile, "web-config", "", "Web configuration file enabling TLS and basic auth on the listener (empty to disable)")
+	flag.StringVar(&webhookURL, "webhook-url", "", "URL to POST a JSON notification to on state transitions (empty to disable)")
+	flag.DurationVar(&webhookDebounce, "webhook-debounce", time.Minute, "Time a new state must last before it is notified")
+	flag.StringVar(&webhookEvents, "webhook-events", "ftth_link,xdsl_status,disk_status,new_host", "Comma separated list of notified transitions")
+	flag.BoolVar(&events, "events", false, "Listen to the event stream for host join/leave events (Freebox OS v8+)")
+	flag.StringVar(&ddnsProviders, "ddns-providers", "ovh,dyndns,noip", "Comma separated list of dynamic DNS providers to report (empty to disable)")
+	flag.BoolVar(&switchMacInfo, "switch-mac-info", false, "Export the MAC addresses learned on each switch port")
+	flag.BoolVar(&systemSerial, "system-serial", false, "Export the Freebox Server serial number in freebox_system_info")
+	flag.BoolVar(&wifiSurvey, "wifi-survey", false, "Collect wifi channel usage and neighbor access points")
+	flag.DurationVar(&wifiSurveyInterval, "wifi-survey-interval", 5*time.Minute, "Interval between two wifi surveys")
+	flag.IntVar(&wifiNeighborsLimit, "wifi-neighbors-limit", 50, "Maximum number of neighbor access points exported per access point, strongest first (0 for no limit)")
+	flag.BoolVar(&home, "home", false, "Collect home automation metrics (needs the \"Gestion de l'alarme et maison connectée\" permission)")
+	flag.BoolVar(&camera, "camera", false, "Collect camera metrics (needs the \"Accès aux caméras\" permission)")
+	flag.BoolVar(&pvr, "pvr", false, "Collect PVR recordings metrics (needs the \"Programmation des enregistrements\" permission)")
+	flag.BoolVar(&parental, "parental", false, "Collect parental control metrics (needs the \"Contrôle parental\" permission)")
+	flag.BoolVar(&freeboxPlayer, "player", false, "Collect Freebox Player metrics (needs the \"Contrôle du Freebox Player\" permission)")
 }
 
 func main() {
@@ -62,6 +110,24 @@ func main() {
 		header: "X-Fbx-App-Auth",
 	}
 
+	myConnectionRequest := &postRequest{
+		method: "GET",
+		url:    mafreebox + "api/v4/connection/",
+		header: "X-Fbx-App-Auth",
+	}
+
+	myConnectionConfigRequest := &postRequest{
+		method: "GET",
+		url:    mafreebox + "api/v4/connection/config/",
+		header: "X-Fbx-App-Auth",
+	}
+
+	myConnectionIPv6ConfigRequest := &postRequest{
+		method: "GET",
+		url:    mafreebox + "api/v4/connection/ipv6/config/",
+		header: "X-Fbx-App-Auth",
+	}
+
 	myConnectionXdslRequest := &postRequest{
 		method: "GET",
 		url:    mafreebox + "api/v4/connection/xdsl/",
@@ -74,6 +140,12 @@ func main() {
 		header: "X-Fbx-App-Auth",
 	}
 
+	myConnectionLteRequest := &postRequest{
+		method: "GET",
+		url:    mafreebox + "api/v8/connection/lte/config/",
+		header: "X-Fbx-App-Auth",
+	}
+
 	myFreeplugRequest := &postRequest{
 		method: "GET",
 		url:    mafreebox + "api/v4/freeplug/",
@@ -98,28 +170,309 @@ func main() {
 		header: "X-Fbx-App-Auth",
 	}
 
+	myPhoneConfigRequest := &postRequest{
+		method: "GET",
+		url:    mafreebox + "api/v4/phone/config/",
+		header: "X-Fbx-App-Auth",
+	}
+
+	myPhonesRequest := &postRequest{
+		method: "GET",
+		url:    mafreebox + "api/v4/phone/",
+		header: "X-Fbx-App-Auth",
+	}
+
+	myLcdConfigRequest := &postRequest{
+		method: "GET",
+		url:    mafreebox + "api/v8/lcd/config/",
+		header: "X-Fbx-App-Auth",
+	}
+
+	myVMRequest := &postRequest{
+		method: "GET",
+		url:    mafreebox + "api/v8/vm/",
+		header: "X-Fbx-App-Auth",
+	}
+
+	myVMInfoRequest := &postRequest{
+		method: "GET",
+		url:    mafreebox + "api/v8/vm/info/",
+		header: "X-Fbx-App-Auth",
+	}
+
+	myVMDiskInfoRequest := &postRequest{
+		method: "POST",
+		url:    mafreebox + "api/v8/vm/disk/info/",
+		header: "X-Fbx-App-Auth",
+	}
+
 	myWifiRequest := &postRequest{
 		method: "GET",
 		url:    mafreebox + "api/v2/wifi/ap/",
 		header: "X-Fbx-App-Auth",
 	}
 
+	myWifiBssRequest := &postRequest{
+		method: "GET",
+		url:    mafreebox + "api/v2/wifi/bss/",
+		header: "X-Fbx-App-Auth",
+	}
+
+	myWifiConfigRequest := &postRequest{
+		method: "GET",
+		url:    mafreebox + "api/v2/wifi/config/",
+		header: "X-Fbx-App-Auth",
+	}
+
+	myHomeNodesRequest := &postRequest{
+		method: "GET",
+		url:    mafreebox + "api/v8/home/nodes/",
+		header: "X-Fbx-App-Auth",
+	}
+
+	myHomeTilesetRequest := &postRequest{
+		method: "GET",
+		url:    mafreebox + "api/v8/home/tileset/all/",
+		header: "X-Fbx-App-Auth",
+	}
+
+	myPvrProgrammedRequest := &postRequest{
+		method: "GET",
+		url:    mafreebox + "api/v4/pvr/programmed/",
+		header: "X-Fbx-App-Auth",
+	}
+
+	myPvrFinishedRequest := &postRequest{
+		method: "GET",
+		url:    mafreebox + "api/v4/pvr/finished/",
+		header: "X-Fbx-App-Auth",
+	}
+
+	myPvrConfigRequest := &postRequest{
+		method: "GET",
+		url:    mafreebox + "api/v4/pvr/config/",
+		header: "X-Fbx-App-Auth",
+	}
+
+	myProfileRequest := &postRequest{
+		method: "GET",
+		url:    mafreebox + "api/v6/profile/",
+		header: "X-Fbx-App-Auth",
+	}
+
+	myNetworkControlRequest := &postRequest{
+		method: "GET",
+		url:    mafreebox + "api/v6/network_control/",
+		header: "X-Fbx-App-Auth",
+	}
+
+	myPlayerRequest := &postRequest{
+		method: "GET",
+		url:    mafreebox + "api/v6/player/",
+		header: "X-Fbx-App-Auth",
+	}
+
 	myVpnRequest := &postRequest{
 		method: "GET",
 		url:    mafreebox + "api/v4/vpn/connection/",
 		header: "X-Fbx-App-Auth",
 	}
 
+	myVpnServerConfigRequest := &postRequest{
+		method: "GET",
+		url:    mafreebox + "api/v4/vpn/",
+		header: "X-Fbx-App-Auth",
+	}
+
+	myVpnClientStatusRequest := &postRequest{
+		method: "GET",
+		url:    mafreebox + "api/v4/vpn_client/status/",
+		header: "X-Fbx-App-Auth",
+	}
+
+	mySambaRequest := &postRequest{
+		method: "GET",
+		url:    mafreebox + "api/v4/netshare/samba/",
+		header: "X-Fbx-App-Auth",
+	}
+
+	myAfpRequest := &postRequest{
+		method: "GET",
+		url:    mafreebox + "api/v4/netshare/afp/",
+		header: "X-Fbx-App-Auth",
+	}
+
+	myFtpRequest := &postRequest{
+		method: "GET",
+		url:    mafreebox + "api/v4/ftp/config/",
+		header: "X-Fbx-App-Auth",
+	}
+
+	myUpnpavRequest := &postRequest{
+		method: "GET",
+		url:    mafreebox + "api/v4/upnpav/config/",
+		header: "X-Fbx-App-Auth",
+	}
+
+	myAirmediaRequest := &postRequest{
+		method: "GET",
+		url:    mafreebox + "api/v4/airmedia/config/",
+		header: "X-Fbx-App-Auth",
+	}
+
+	myFwRedirRequest := &postRequest{
+		method: "GET",
+		url:    mafreebox + "api/v4/fw/redir/",
+		header: "X-Fbx-App-Auth",
+	}
+
+	myFwDmzRequest := &postRequest{
+		method: "GET",
+		url:    mafreebox + "api/v4/fw/dmz/",
+		header: "X-Fbx-App-Auth",
+	}
+
+	myUpnpigdRedirRequest := &postRequest{
+		method: "GET",
+		url:    mafreebox + "api/v4/upnpigd/redir/",
+		header: "X-Fbx-App-Auth",
+	}
+
 	mySwitchStatusRequest := &postRequest{
 		method: "GET",
 		url:    mafreebox + "api/v8/switch/status/",
 		header: "X-Fbx-App-Auth",
 	}
 
+	if relabelFile != "" {
+		var err error
+		hostRelabel, err = loadRelabelConfig(relabelFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	myLimiter, err := newHostLimiter(hostAllow, hostDeny, hostLimit, collectorLimits, aggregateHosts)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	myNotifier, err := newNotifier(webhookURL, webhookDebounce, webhookEvents)
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	var mySessionToken string
 
+	var myInventory *inventory
+	if inventoryFile != "" {
+		var err error
+		myInventory, err = loadInventory(inventoryFile, inventoryState)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	var lastWifiSurvey time.Time
+	var knownUpnpigdRedirs map[string]bool
+	// LTE support is discovered on the first successful loop: boxes
+	// without 4G aggregation do not have the LTE endpoint
+	lteDiscovered, lteSupported := false, false
+	homeTriggers := make(map[int]float64)
+	homeLastTriggers := make(map[int]time.Time)
+	ddnsPreviousStatus := make(map[string]string)
+	// the LCD endpoint only exists on newer boxes, it is disabled once
+	// the box answers it does not know it
+	lcdSupported := true
+	var lastLcdConfig *lcdConfigResult
+
 	go func() {
 		for {
+			myLimiter.reset()
+
+			// connection metrics
+			connectionStats, err := getConnection(myAuthInfo, myConnectionRequest, &mySessionToken)
+			if err != nil {
+				log.Printf("An error occured with connection metrics: %v", err)
+			}
+
+			if connectionStats.Success {
+				result := connectionStats.Result
+
+				for _, state := range []string{"going_up", "up", "going_down", "down"} {
+					connectionStateGauges.WithLabelValues(state).Set(bool2float(result.State == state))
+				}
+
+				connectionInfoGauges.Reset()
+				connectionInfoGauges.WithLabelValues(result.Media, result.Type, result.IPv4, result.IPv6).Set(1)
+
+				connectionBytesGauges.WithLabelValues("up").Set(float64(result.BytesUp))
+				connectionBytesGauges.WithLabelValues("down").Set(float64(result.BytesDown))
+				connectionRateGauges.WithLabelValues("up").Set(float64(result.RateUp))
+				connectionRateGauges.WithLabelValues("down").Set(float64(result.RateDown))
+				connectionBandwidthGauges.WithLabelValues("up").Set(float64(result.BandwidthUp))
+				connectionBandwidthGauges.WithLabelValues("down").Set(float64(result.BandwidthDown))
+			}
+
+			connectionConfigStats, err := getConnectionConfig(myAuthInfo, myConnectionConfigRequest, &mySessionToken)
+			if err != nil {
+				log.Printf("An error occured with connection config metrics: %v", err)
+			}
+
+			if connectionConfigStats.Success {
+				result := connectionConfigStats.Result
+				connectionSettingGauges.WithLabelValues("remote_access").Set(bool2float(result.RemoteAccess))
+				connectionSettingGauges.WithLabelValues("api_remote_access").Set(bool2float(result.APIRemoteAccess))
+				connectionSettingGauges.WithLabelValues("ping").Set(bool2float(result.Ping))
+				connectionSettingGauges.WithLabelValues("wol").Set(bool2float(result.Wol))
+				connectionSettingGauges.WithLabelValues("adblock").Set(bool2float(result.Adblock))
+				connectionRemoteAccessPortGauges.Reset()
+				connectionRemoteAccessPortGauges.WithLabelValues("http").Set(float64(result.RemoteAccessPort))
+				if result.HTTPSAvailable {
+					connectionRemoteAccessPortGauges.WithLabelValues("https").Set(float64(result.HTTPSPort))
+				}
+			}
+
+			// ddns metrics
+			for _, provider := range strings.Split(ddnsProviders, ",") {
+				provider = strings.TrimSpace(provider)
+				if provider == "" {
+					continue
+				}
+				myDdnsStatusRequest := &postRequest{
+					method: "GET",
+					url:    mafreebox + "api/v4/connection/ddns/" + provider + "/status/",
+					header: "X-Fbx-App-Auth",
+				}
+				ddnsStats, err := getConnectionDdnsStatus(myAuthInfo, myDdnsStatusRequest, &mySessionToken)
+				if err != nil {
+					log.Printf("An error occured with %s DDNS metrics: %v", provider, err)
+					continue
+				}
+				if !ddnsStats.Success {
+					continue
+				}
+
+				ddnsStatusGauges.DeleteLabelValues(provider, ddnsPreviousStatus[provider])
+				ddnsStatusGauges.WithLabelValues(provider, ddnsStats.Result.Status).Set(1)
+				ddnsPreviousStatus[provider] = ddnsStats.Result.Status
+				ddnsLastRefreshGauges.WithLabelValues(provider).Set(float64(ddnsStats.Result.LastRefresh))
+				ddnsNextRefreshGauges.WithLabelValues(provider).Set(float64(ddnsStats.Result.NextRefresh))
+			}
+
+			connectionIPv6Stats, err := getConnectionIPv6Config(myAuthInfo, myConnectionIPv6ConfigRequest, &mySessionToken)
+			if err != nil {
+				log.Printf("An error occured with connection IPv6 metrics: %v", err)
+			}
+
+			if connectionIPv6Stats.Success {
+				connectionIPv6EnabledGauge.Set(bool2float(connectionIPv6Stats.Result.IPv6Enabled))
+				connectionIPv6DelegationGauges.Reset()
+				for _, delegation := range connectionIPv6Stats.Result.Delegations {
+					connectionIPv6DelegationGauges.WithLabelValues(delegation.Prefix, delegation.NextHop).Set(1)
+				}
+			}
+
 			// There is no DSL metric on fiber Freebox
 			// If you use a fiber Freebox, use -fiber flag to turn off this metric
 			if !fiber {
@@ -131,6 +484,7 @@ func main() {
 
 				if connectionXdslStats.Success {
 					status := connectionXdslStats.Result.Status
+					myNotifier.observe("xdsl_status", status.Status)
 					result := connectionXdslStats.Result
 					down := result.Down
 					up := result.Up
@@ -198,6 +552,7 @@ func main() {
 					SfpAlimOk := connectionFtthStats.Result.SfpAlimOk
 					SfpSerial := connectionFtthStats.Result.SfpSerial
 					SfpPresent := connectionFtthStats.Result.SfpPresent
+					myNotifier.observe("ftth_link", strconv.FormatBool(Link))
 
 					connectionFtthRxPwrGauge.Set(float64(SfpPwrRx) / 100)
 					connectionFtthTxPwrGauge.Set(float64(SfpPwrTx) / 100)
@@ -234,14 +589,84 @@ func main() {
 				}
 			}
 
+			// connectionLte metrics
+			if !lteDiscovered || lteSupported {
+				connectionLteStats, err := getConnectionLte(myAuthInfo, myConnectionLteRequest, &mySessionToken)
+				if !lteDiscovered {
+					// any other error, or a session renewal answering without
+					// error nor success, is retried on the next loop
+					if lteUnsupported(err) {
+						lteDiscovered = true
+						log.Printf("4G aggregation is not supported by this Freebox, LTE metrics are disabled: %v", err)
+					} else if connectionLteStats.Success {
+						lteDiscovered, lteSupported = true, true
+					} else if err != nil {
+						log.Printf("An error occured with connectionLte metrics: %v", err)
+					}
+				} else if err != nil {
+					log.Printf("An error occured with connectionLte metrics: %v", err)
+				}
+
+				if connectionLteStats.Success {
+					result := connectionLteStats.Result
+
+					connectionLteEnabledGauge.Set(bool2float(result.Enabled))
+					connectionLteStateGauges.Reset()
+					connectionLteStateGauges.WithLabelValues(result.State).Set(1)
+					connectionLteAssociatedGauge.Set(bool2float(result.Radio.Associated))
+
+					connectionLteBandEnabledGauges.Reset()
+					connectionLteBandBandwidthGauges.Reset()
+					connectionLteBandRsrpGauges.Reset()
+					connectionLteBandRsrqGauges.Reset()
+					connectionLteBandRssiGauges.Reset()
+					connectionLteBandSinrGauges.Reset()
+					for _, band := range result.Radio.Bands {
+						labels := prometheus.Labels{"band": strconv.Itoa(band.Band), "pci": strconv.Itoa(band.Pci)}
+						connectionLteBandEnabledGauges.With(labels).Set(bool2float(band.Enabled))
+						connectionLteBandBandwidthGauges.With(labels).Set(float64(band.Bandwidth))
+						connectionLteBandRsrpGauges.With(labels).Set(float64(band.Rsrp))
+						connectionLteBandRsrqGauges.With(labels).Set(float64(band.Rsrq))
+						connectionLteBandRssiGauges.With(labels).Set(float64(band.Rssi))
+						connectionLteBandSinrGauges.With(labels).Set(float64(band.Sinr))
+					}
+
+					for name, tunnel := range map[string]lteTunnel{"lte": result.Tunnel.Lte, "xdsl": result.Tunnel.Xdsl} {
+						connectionLteTunnelConnectedGauges.WithLabelValues(name).Set(bool2float(tunnel.Connected))
+						connectionLteTunnelRateGauges.WithLabelValues(name, "rx").Set(float64(tunnel.RxFlowsRate))
+						connectionLteTunnelRateGauges.WithLabelValues(name, "tx").Set(float64(tunnel.TxFlowsRate))
+						connectionLteTunnelMaxRateGauges.WithLabelValues(name, "rx").Set(float64(tunnel.RxMaxRate))
+						connectionLteTunnelMaxRateGauges.WithLabelValues(name, "tx").Set(float64(tunnel.TxMaxRate))
+					}
+				}
+			}
+
 			// freeplug metrics
 			freeplugStats, err := getFreeplug(myAuthInfo, myFreeplugRequest, &mySessionToken)
 			if err != nil {
 				log.Printf("An error occured with freeplug metrics: %v", err)
 			}
 
+			if freeplugStats.Success {
+				freeplugInfoGauges.Reset()
+				freeplugEthSpeedGauge.Reset()
+				freeplugEthFullDuplexGauge.Reset()
+				freeplugEthPortUpGauge.Reset()
+				freeplugInactiveGauge.Reset()
+				freeplugHasNetworkGauge.Reset()
+				freeplugRxRateGauge.Reset()
+				freeplugTxRateGauge.Reset()
+			}
 			for _, freeplugNetwork := range freeplugStats.Result {
 				for _, freeplugMember := range freeplugNetwork.Members {
+					freeplugInfoGauges.
+						WithLabelValues(freeplugMember.ID, freeplugMember.NetID, freeplugRole(freeplugMember.NetRole), freeplugMember.Model, strconv.FormatBool(freeplugMember.Local)).
+						Set(1)
+					freeplugEthSpeedGauge.WithLabelValues(freeplugMember.ID).Set(float64(freeplugMember.EthSpeed) * 1e6)
+					freeplugEthFullDuplexGauge.WithLabelValues(freeplugMember.ID).Set(bool2float(freeplugMember.EthFullDuplex))
+					freeplugEthPortUpGauge.WithLabelValues(freeplugMember.ID).Set(bool2float(freeplugMember.EthPortStatus == "up"))
+					freeplugInactiveGauge.WithLabelValues(freeplugMember.ID).Set(float64(freeplugMember.Inactive))
+
 					if freeplugMember.HasNetwork {
 						freeplugHasNetworkGauge.WithLabelValues(freeplugMember.ID).Set(float64(1))
 					} else {
@@ -282,7 +707,51 @@ func main() {
 			if err != nil {
 				log.Printf("An error occured with LAN metrics: %v", err)
 			}
+			// hosts no longer admitted must not keep their series
+			if err == nil {
+				lanReachableGauges.Reset()
+			}
+			if err == nil && myInventory != nil {
+				lanHostKnownGauges.Reset()
+				lanHostInventoryInfoGauges.Reset()
+				lanHostFirstSeenGauges.Reset()
+			}
+			lanHosts := make(map[string]string)
+			unknownHosts := 0
+			reachableHosts := 0
 			for _, v := range lanAvailable {
+				v.PrimaryName = hostRelabel.name(v.L2Ident.ID, v.PrimaryName)
+				if v.Reachable {
+					reachableHosts++
+				}
+				export := myLimiter.admit("lan", v.L2Ident.ID, v.PrimaryName)
+				if myInventory == nil {
+					lanHosts[v.L2Ident.ID] = v.PrimaryName
+				} else {
+					host, known := myInventory.lookup(v.L2Ident.ID)
+					if !known {
+						// only hosts missing from the inventory are new
+						lanHosts[v.L2Ident.ID] = v.PrimaryName
+						if v.Reachable {
+							unknownHosts++
+						}
+					}
+					firstSeen, err := myInventory.seen(v.L2Ident.ID, time.Now())
+					if err != nil {
+						log.Printf("An error occured with the inventory state: %v", err)
+					}
+					if export {
+						lanHostKnownGauges.WithLabelValues(v.L2Ident.ID).Set(bool2float(known))
+						if known {
+							lanHostInventoryInfoGauges.WithLabelValues(v.L2Ident.ID, host.Name, host.Owner, strings.Join(host.Tags, ",")).Set(1)
+						}
+						lanHostFirstSeenGauges.WithLabelValues(v.L2Ident.ID).Set(float64(firstSeen))
+					}
+				}
+				if !export {
+					continue
+				}
+
 				var Ip string
 				if len(v.L3c) > 0 {
 					Ip = v.L3c[0].Addr
@@ -296,12 +765,28 @@ func main() {
 				}
 			}
 
+			if err == nil {
+				lanHostsGauges.WithLabelValues("true").Set(float64(reachableHosts))
+				lanHostsGauges.WithLabelValues("false").Set(float64(len(lanAvailable) - reachableHosts))
+				myNotifier.observeHosts(lanHosts)
+				if myInventory != nil {
+					lanUnknownHostsGauge.Set(float64(unknownHosts))
+				}
+			}
+
 			// system metrics
+			hasVM := false
+			hasDect := !v6 // the v4 API does not tell, always try
 			if v6 {
 				systemStats, err := getSystemV6(myAuthInfo, mySystemV6Request, &mySessionToken)
 				if err != nil {
 					log.Printf("An error occured with System metrics: %v", err)
 				}
+				hasVM = systemStats.Result.ModelInfo.HasVm
+				hasDect = systemStats.Result.ModelInfo.HasDect
+				if systemStats.Success {
+					myNotifier.observe("disk_status", systemStats.Result.DiskStatus)
+				}
 
 				for _, sensor := range systemStats.Result.Sensors {
 					systemTempGauges.WithLabelValues(sensor.Name).Set(float64(sensor.Value))
@@ -313,12 +798,38 @@ func main() {
 				systemUptimeGauges.
 					WithLabelValues(systemStats.Result.FirmwareVersion).
 					Set(float64(systemStats.Result.UptimeVal))
+
+				if systemStats.Success {
+					result := systemStats.Result
+					serial := ""
+					if systemSerial {
+						serial = result.Serial
+					}
+					systemInfoGauges.Reset()
+					systemInfoGauges.
+						WithLabelValues(result.ModelInfo.Name, result.ModelInfo.PrettyName, result.BoardName, serial, result.Mac, result.ModelInfo.NetOperator, result.ModelInfo.WifiType, result.FirmwareVersion).
+						Set(1)
+
+					systemExpansionPresentGauges.Reset()
+					systemExpansionSupportedGauges.Reset()
+					systemExpansionProbeDoneGauges.Reset()
+					for _, expansion := range result.Expansions {
+						labels := prometheus.Labels{"slot": strconv.Itoa(expansion.Slot), "type": expansion.Type, "bundle": expansion.Bundle}
+						systemExpansionPresentGauges.With(labels).Set(bool2float(expansion.Present))
+						systemExpansionSupportedGauges.With(labels).Set(bool2float(expansion.Supported))
+						systemExpansionProbeDoneGauges.With(labels).Set(bool2float(expansion.ProbeDone))
+					}
+				}
 			} else {
 				systemStats, err := getSystem(myAuthInfo, mySystemRequest, &mySessionToken)
 				if err != nil {
 					log.Printf("An error occured with System metrics: %v", err)
 				}
 
+				if systemStats.Success {
+					myNotifier.observe("disk_status", systemStats.Result.DiskStatus)
+				}
+
 				systemTempGauges.WithLabelValues("Température CPU B").Set(float64(systemStats.Result.TempCpub))
 				systemTempGauges.WithLabelValues("Température CPU M").Set(float64(systemStats.Result.TempCpum))
 				systemTempGauges.WithLabelValues("Température Switch").Set(float64(systemStats.Result.TempSW))
@@ -328,6 +839,117 @@ func main() {
 				systemUptimeGauges.
 					WithLabelValues(systemStats.Result.FirmwareVersion).
 					Set(float64(systemStats.Result.UptimeVal))
+
+				if systemStats.Success {
+					result := systemStats.Result
+					serial := ""
+					if systemSerial {
+						serial = result.Serial
+					}
+					// the v4 API has no model info, the box flavor is the closest
+					systemInfoGauges.Reset()
+					systemInfoGauges.
+						WithLabelValues(result.BoxFlavor, "", result.BoardName, serial, result.Mac, "", "", result.FirmwareVersion).
+						Set(1)
+				}
+			}
+
+			// phone metrics, only on boxes with a DECT base
+			if hasDect {
+				phoneConfigStats, err := getPhoneConfig(myAuthInfo, myPhoneConfigRequest, &mySessionToken)
+				if err != nil {
+					log.Printf("An error occured with phone metrics: %v", err)
+				}
+				if phoneConfigStats.Success {
+					result := phoneConfigStats.Result
+					phoneNetworkUpGauge.Set(bool2float(result.NetworkIsUp))
+					phoneDectGauges.WithLabelValues("enabled").Set(bool2float(result.DectEnabled))
+					phoneDectGauges.WithLabelValues("eco_mode").Set(bool2float(result.DectEcoMode))
+					phoneDectGauges.WithLabelValues("registration_open").Set(bool2float(result.DectRegistration))
+					phoneDectGauges.WithLabelValues("nemo_mode").Set(bool2float(result.DectNemoMode))
+				}
+
+				// the config does not tell the handsets, they are listed with the phones
+				phonesStats, err := getPhones(myAuthInfo, myPhonesRequest, &mySessionToken)
+				if err != nil {
+					log.Printf("An error occured with phone handsets metrics: %v", err)
+				}
+				if phonesStats.Success {
+					phoneDectHandsetsGauge.Set(float64(phonesStats.dectHandsets()))
+				}
+			}
+
+			// lcd metrics
+			if lcdSupported {
+				lcdConfigStats, err := getLcdConfig(myAuthInfo, myLcdConfigRequest, &mySessionToken)
+				if err == errNotFound {
+					lcdSupported = false
+					log.Printf("LCD configuration is not supported by this Freebox, LCD metrics are disabled: %v", err)
+				} else if err != nil {
+					log.Printf("An error occured with LCD metrics: %v", err)
+				}
+				if lcdConfigStats.Success {
+					result := lcdConfigStats.Result
+					lcdBrightnessGauge.Set(float64(result.Brightness))
+					lcdLedStripEnabledGauge.Set(bool2float(result.LedStripEnabled))
+					lcdLedStripBrightnessGauge.Set(float64(result.LedStripBrightness))
+					lcdInfoGauges.Reset()
+					lcdInfoGauges.
+						WithLabelValues(strconv.Itoa(result.Orientation), strconv.FormatBool(result.OrientationForced), strconv.FormatBool(result.HideWifiKey), result.LedStripAnimation).
+						Set(1)
+
+					// lets a night mode automation check its changes were applied
+					if lastLcdConfig == nil || *lastLcdConfig != result {
+						lcdLastChangeGauge.SetToCurrentTime()
+						lastLcdConfig = &result
+					}
+				}
+			}
+
+			// vm metrics, only on boxes able to host virtual machines
+			if hasVM {
+				vmsStats, err := getVMs(myAuthInfo, myVMRequest, &mySessionToken)
+				if err != nil {
+					log.Printf("An error occured with VM metrics: %v", err)
+				}
+				if vmsStats.Success {
+					vmStatusGauges.Reset()
+					vmVcpusGauges.Reset()
+					vmMemoryGauges.Reset()
+					vmDiskGauges.Reset()
+				}
+				for _, machine := range vmsStats.Result {
+					for _, status := range []string{"stopped", "running", "starting", "stopping"} {
+						vmStatusGauges.WithLabelValues(machine.Name, status).Set(bool2float(machine.Status == status))
+					}
+					vmVcpusGauges.WithLabelValues(machine.Name).Set(float64(machine.Vcpus))
+					vmMemoryGauges.WithLabelValues(machine.Name).Set(float64(machine.Memory) * 1024 * 1024)
+
+					if machine.DiskPath == "" {
+						continue
+					}
+					vmDiskStats, err := getVMDiskInfo(myAuthInfo, myVMDiskInfoRequest, &mySessionToken, machine.DiskPath)
+					if err != nil {
+						log.Printf("An error occured with VM disk metrics: %v", err)
+						continue
+					}
+					if !vmDiskStats.Success {
+						continue
+					}
+					vmDiskGauges.WithLabelValues(machine.Name, "actual").Set(float64(vmDiskStats.Result.ActualSize))
+					vmDiskGauges.WithLabelValues(machine.Name, "virtual").Set(float64(vmDiskStats.Result.VirtualSize))
+				}
+
+				vmInfoStats, err := getVMInfo(myAuthInfo, myVMInfoRequest, &mySessionToken)
+				if err != nil {
+					log.Printf("An error occured with VM info metrics: %v", err)
+				}
+				if vmInfoStats.Success {
+					vmHostCpusGauges.WithLabelValues("used").Set(float64(vmInfoStats.Result.UsedCpus))
+					vmHostCpusGauges.WithLabelValues("total").Set(float64(vmInfoStats.Result.TotalCpus))
+					vmHostMemoryGauges.WithLabelValues("used").Set(float64(vmInfoStats.Result.UsedMemory) * 1024 * 1024)
+					vmHostMemoryGauges.WithLabelValues("total").Set(float64(vmInfoStats.Result.TotalMemory) * 1024 * 1024)
+				}
 			}
 
 			// wifi metrics
@@ -335,6 +957,10 @@ func main() {
 			if err != nil {
 				log.Printf("An error occured with Wifi metrics: %v", err)
 			}
+			// stations are fetched for every access point before the
+			// station gauges are reset, so a failed fetch keeps them
+			wifiApStations := make(map[int][]wifiStation)
+			wifiStationsSuccess := wifiStats.Success
 			for _, accessPoint := range wifiStats.Result {
 				myWifiStationRequest := &postRequest{
 					method: "GET",
@@ -345,14 +971,429 @@ func main() {
 				if err != nil {
 					log.Printf("An error occured with Wifi station metrics: %v", err)
 				}
+				if !wifiStationsStats.Success {
+					wifiStationsSuccess = false
+					continue
+				}
+				wifiApStations[accessPoint.ID] = wifiStationsStats.Result
+
+				wifiApStationsGauges.WithLabelValues(accessPoint.Name).Set(float64(len(wifiStationsStats.Result)))
+				var rxBytes, txBytes, rxRate, txRate int64
 				for _, station := range wifiStationsStats.Result {
-					wifiSignalGauges.With(prometheus.Labels{"access_point": accessPoint.Name, "mac": station.MAC, "hostname": station.Hostname, "state": station.State}).Set(float64(station.Signal))
-					wifiInactiveGauges.With(prometheus.Labels{"access_point": accessPoint.Name, "mac": station.MAC, "hostname": station.Hostname, "state": station.State}).Set(float64(station.Inactive))
-					wifiConnectionDurationGauges.With(prometheus.Labels{"access_point": accessPoint.Name, "mac": station.MAC, "hostname": station.Hostname, "state": station.State}).Set(float64(station.ConnectionDuration))
-					wifiRXBytesGauges.With(prometheus.Labels{"access_point": accessPoint.Name, "mac": station.MAC, "hostname": station.Hostname, "state": station.State}).Set(float64(station.RXBytes))
-					wifiTXBytesGauges.With(prometheus.Labels{"access_point": accessPoint.Name, "mac": station.MAC, "hostname": station.Hostname, "state": station.State}).Set(float64(station.TXBytes))
-					wifiRXRateGauges.With(prometheus.Labels{"access_point": accessPoint.Name, "mac": station.MAC, "hostname": station.Hostname, "state": station.State}).Set(float64(station.RXRate))
-					wifiTXRateGauges.With(prometheus.Labels{"access_point": accessPoint.Name, "mac": station.MAC, "hostname": station.Hostname, "state": station.State}).Set(float64(station.TXRate))
+					rxBytes += station.RXBytes
+					txBytes += station.TXBytes
+					rxRate += station.RXRate
+					txRate += station.TXRate
+				}
+				wifiApStationBytesGauges.WithLabelValues(accessPoint.Name, "rx").Set(float64(rxBytes))
+				wifiApStationBytesGauges.WithLabelValues(accessPoint.Name, "tx").Set(float64(txBytes))
+				wifiApStationRateGauges.WithLabelValues(accessPoint.Name, "rx").Set(float64(rxRate))
+				wifiApStationRateGauges.WithLabelValues(accessPoint.Name, "tx").Set(float64(txRate))
+			}
+			if wifiStationsSuccess {
+				wifiSignalGauges.Reset()
+				wifiInactiveGauges.Reset()
+				wifiConnectionDurationGauges.Reset()
+				wifiRXBytesGauges.Reset()
+				wifiTXBytesGauges.Reset()
+				wifiRXRateGauges.Reset()
+				wifiTXRateGauges.Reset()
+				wifiStationStateGauges.Reset()
+				wifiStationInfoGauges.Reset()
+				wifiStationFlagGauges.Reset()
+				wifiStationPhyRateGauges.Reset()
+				wifiStationMcsGauges.Reset()
+				wifiStationNssGauges.Reset()
+				wifiStationBandwidthGauges.Reset()
+			}
+			for _, accessPoint := range wifiStats.Result {
+				for _, station := range wifiApStations[accessPoint.ID] {
+					station.Hostname = hostRelabel.name(station.MAC, station.Hostname)
+					if !myLimiter.admit("wifi", station.MAC, station.Hostname) {
+						continue
+					}
+					labels := prometheus.Labels{"access_point": accessPoint.Name, "mac": station.MAC, "hostname": station.Hostname}
+
+					wifiSignalGauges.With(labels).Set(float64(station.Signal))
+					wifiInactiveGauges.With(labels).Set(float64(station.Inactive))
+					wifiConnectionDurationGauges.With(labels).Set(float64(station.ConnectionDuration))
+					wifiRXBytesGauges.With(labels).Set(float64(station.RXBytes))
+					wifiTXBytesGauges.With(labels).Set(float64(station.TXBytes))
+					wifiRXRateGauges.With(labels).Set(float64(station.RXRate))
+					wifiTXRateGauges.With(labels).Set(float64(station.TXRate))
+
+					wifiStationStateGauges.WithLabelValues(accessPoint.Name, station.MAC, station.Hostname, station.State).Set(1)
+					wifiStationInfoGauges.WithLabelValues(accessPoint.Name, station.MAC, station.Hostname, station.Bssid, station.Standard).Set(1)
+
+					flags := map[string]bool{
+						"legacy":     station.Flags.Legacy,
+						"ht":         station.Flags.Ht,
+						"vht":        station.Flags.Vht,
+						"he":         station.Flags.He,
+						"authorized": station.Flags.Authorized,
+						"wmm":        station.Flags.Wmm,
+						"power_save": station.Flags.PowerSave,
+					}
+					for flag, value := range flags {
+						wifiStationFlagGauges.WithLabelValues(accessPoint.Name, station.MAC, station.Hostname, flag).Set(bool2float(value))
+					}
+
+					for direction, stats := range map[string]wifiStationStats{"rx": station.LastRx, "tx": station.LastTx} {
+						wifiStationPhyRateGauges.WithLabelValues(accessPoint.Name, station.MAC, station.Hostname, direction).Set(float64(stats.Bitrate) * 1e5)
+						wifiStationMcsGauges.WithLabelValues(accessPoint.Name, station.MAC, station.Hostname, direction).Set(float64(station.mcs(stats)))
+						wifiStationNssGauges.WithLabelValues(accessPoint.Name, station.MAC, station.Hostname, direction).Set(float64(stats.Nss))
+						if width, err := strconv.Atoi(stats.Width); err == nil {
+							wifiStationBandwidthGauges.WithLabelValues(accessPoint.Name, station.MAC, station.Hostname, direction).Set(float64(width))
+						}
+					}
+				}
+			}
+
+			// wifi radio metrics
+			wifiApNames := make(map[int]string)
+			if wifiStats.Success {
+				wifiApStateGauges.Reset()
+				wifiApChannelGauges.Reset()
+				wifiApChannelWidthGauges.Reset()
+				wifiApCapabilityGauges.Reset()
+			}
+			for _, accessPoint := range wifiStats.Result {
+				wifiApNames[accessPoint.ID] = accessPoint.Name
+				band := accessPoint.Config.Band
+
+				wifiApStateGauges.WithLabelValues(accessPoint.Name, band, accessPoint.Status.State).Set(1)
+				wifiApChannelGauges.WithLabelValues(accessPoint.Name, band, "primary").Set(float64(accessPoint.Status.PrimaryChannel))
+				wifiApChannelGauges.WithLabelValues(accessPoint.Name, band, "secondary").Set(float64(accessPoint.Status.SecondaryChannel))
+
+				if width, err := strconv.Atoi(accessPoint.Status.ChannelWidth); err == nil {
+					wifiApChannelWidthGauges.WithLabelValues(accessPoint.Name, band).Set(float64(width))
+				}
+
+				for capBand, capabilities := range accessPoint.Capabilities {
+					for capability, value := range capabilities {
+						if enabled, ok := value.(bool); ok {
+							wifiApCapabilityGauges.WithLabelValues(accessPoint.Name, capBand, capability).Set(bool2float(enabled))
+						}
+					}
+				}
+			}
+
+			// wifi survey metrics, on their own slower interval. Every access
+			// point is surveyed before the gauges are reset, so a failed fetch
+			// keeps them until the next survey
+			if wifiSurvey && wifiStats.Success && time.Since(lastWifiSurvey) >= wifiSurveyInterval {
+				lastWifiSurvey = time.Now()
+				wifiApChannelUsages := make(map[int][]wifiChannelUsage)
+				wifiApNeighbors := make(map[int][]wifiNeighbor)
+				channelUsageSuccess, neighborsSuccess := true, true
+				for _, accessPoint := range wifiStats.Result {
+					myWifiChannelUsageRequest := &postRequest{
+						method: "GET",
+						url:    mafreebox + "api/v2/wifi/ap/" + strconv.Itoa(accessPoint.ID) + "/channel_usage/",
+						header: "X-Fbx-App-Auth",
+					}
+					channelUsageStats, err := getWifiChannelUsage(myAuthInfo, myWifiChannelUsageRequest, &mySessionToken)
+					if err != nil {
+						log.Printf("An error occured with Wifi channel usage metrics: %v", err)
+					}
+					if channelUsageStats.Success {
+						wifiApChannelUsages[accessPoint.ID] = channelUsageStats.Result
+					} else {
+						channelUsageSuccess = false
+					}
+
+					myWifiNeighborsRequest := &postRequest{
+						method: "GET",
+						url:    mafreebox + "api/v2/wifi/ap/" + strconv.Itoa(accessPoint.ID) + "/neighbors/",
+						header: "X-Fbx-App-Auth",
+					}
+					neighborsStats, err := getWifiNeighbors(myAuthInfo, myWifiNeighborsRequest, &mySessionToken)
+					if err != nil {
+						log.Printf("An error occured with Wifi neighbors metrics: %v", err)
+					}
+					if neighborsStats.Success {
+						wifiApNeighbors[accessPoint.ID] = neighborsStats.Result
+					} else {
+						neighborsSuccess = false
+					}
+				}
+
+				if channelUsageSuccess {
+					wifiChannelBusyGauges.Reset()
+					wifiChannelRxBusyGauges.Reset()
+					wifiChannelTxGauges.Reset()
+					wifiChannelNoiseGauges.Reset()
+					for _, accessPoint := range wifiStats.Result {
+						for _, usage := range wifiApChannelUsages[accessPoint.ID] {
+							channel := strconv.Itoa(usage.Channel)
+							wifiChannelBusyGauges.WithLabelValues(accessPoint.Name, usage.Band, channel).Set(float64(usage.BusyPercent))
+							wifiChannelRxBusyGauges.WithLabelValues(accessPoint.Name, usage.Band, channel).Set(float64(usage.RxBusyPercent))
+							wifiChannelTxGauges.WithLabelValues(accessPoint.Name, usage.Band, channel).Set(float64(usage.TxPercent))
+							wifiChannelNoiseGauges.WithLabelValues(accessPoint.Name, usage.Band, channel).Set(float64(usage.NoiseLevel))
+						}
+					}
+				}
+
+				if neighborsSuccess {
+					wifiNeighborsGauges.Reset()
+					wifiNeighborSignalGauges.Reset()
+					for _, accessPoint := range wifiStats.Result {
+						neighbors := wifiApNeighbors[accessPoint.ID]
+						wifiNeighborsGauges.WithLabelValues(accessPoint.Name).Set(float64(len(neighbors)))
+
+						if wifiNeighborsLimit > 0 && len(neighbors) > wifiNeighborsLimit {
+							neighbors = neighbors[:wifiNeighborsLimit]
+						}
+						for _, neighbor := range neighbors {
+							wifiNeighborSignalGauges.
+								WithLabelValues(accessPoint.Name, neighbor.Bssid, neighbor.Ssid, neighbor.Band, strconv.Itoa(neighbor.Channel)).
+								Set(float64(neighbor.Signal))
+						}
+					}
+				}
+			}
+
+			// wifi BSS metrics
+			wifiBssStats, err := getWifiBss(myAuthInfo, myWifiBssRequest, &mySessionToken)
+			if err != nil {
+				log.Printf("An error occured with Wifi BSS metrics: %v", err)
+			}
+			if wifiBssStats.Success {
+				wifiBssInfoGauges.Reset()
+				wifiBssEnabledGauges.Reset()
+				wifiBssStationsGauges.Reset()
+			}
+			for _, bss := range wifiBssStats.Result {
+				apName := wifiApNames[bss.PhyID]
+				labels := prometheus.Labels{"access_point": apName, "bssid": bss.ID, "ssid": bss.Config.Ssid}
+
+				wifiBssInfoGauges.With(prometheus.Labels{
+					"access_point": apName,
+					"bssid":        bss.ID,
+					"ssid":         bss.Config.Ssid,
+					"encryption":   bss.Config.Encryption,
+					"state":        bss.Status.State,
+					"hidden":       strconv.FormatBool(bss.Config.HideSsid),
+					"main":         strconv.FormatBool(bss.Status.IsMainBss),
+				}).Set(1)
+				wifiBssEnabledGauges.With(labels).Set(bool2float(bss.Config.Enabled))
+				wifiBssStationsGauges.WithLabelValues(apName, bss.ID, bss.Config.Ssid, "all").Set(float64(bss.Status.StaCount))
+				wifiBssStationsGauges.WithLabelValues(apName, bss.ID, bss.Config.Ssid, "authorized").Set(float64(bss.Status.AuthorizedStaCount))
+			}
+
+			// wifi global config
+			wifiConfigStats, err := getWifiConfig(myAuthInfo, myWifiConfigRequest, &mySessionToken)
+			if err != nil {
+				log.Printf("An error occured with Wifi config metrics: %v", err)
+			}
+			if wifiConfigStats.Success {
+				wifiEnabledGauge.Set(bool2float(wifiConfigStats.Result.Enabled))
+				wifiMacFilterStateGauges.Reset()
+				wifiMacFilterStateGauges.WithLabelValues(wifiConfigStats.Result.MacFilterState).Set(1)
+			}
+
+			// home automation metrics, cameras are home nodes too
+			var homeNodesStats homeNodes
+			if home || camera {
+				homeNodesStats, err = getHomeNodes(myAuthInfo, myHomeNodesRequest, &mySessionToken)
+				if err != nil {
+					log.Printf("An error occured with home nodes metrics: %v", err)
+				}
+			}
+
+			if camera {
+				if homeNodesStats.Success {
+					cameraOnlineGauges.Reset()
+					cameraStreamGauges.Reset()
+				}
+				for _, node := range homeNodesStats.Result {
+					if node.Category != "camera" {
+						continue
+					}
+					cameraOnlineGauges.WithLabelValues(node.Label).Set(bool2float(node.Status == "active"))
+					cameraStreamGauges.WithLabelValues(node.Label).Set(bool2float(node.Props.Stream != ""))
+				}
+			}
+
+			if home {
+				nodes := make(map[int]homeNode)
+				if homeNodesStats.Success {
+					homeNodeStatusGauges.Reset()
+				}
+				for _, node := range homeNodesStats.Result {
+					nodes[node.ID] = node
+					homeNodeStatusGauges.WithLabelValues(node.Label, node.Category, node.Status).Set(1)
+				}
+
+				homeTilesetStats, err := getHomeTileset(myAuthInfo, myHomeTilesetRequest, &mySessionToken)
+				if err != nil {
+					log.Printf("An error occured with home tileset metrics: %v", err)
+				}
+
+				if homeTilesetStats.Success {
+					homeEndpointInfoGauges.Reset()
+					homeEndpointValueGauges.Reset()
+					homeNodeBatteryGauges.Reset()
+					homeNodeLastTriggerGauges.Reset()
+					homeAlarmArmedGauges.Reset()
+				}
+				for _, tile := range homeTilesetStats.Result {
+					node, ok := nodes[tile.NodeID]
+					if !ok {
+						continue
+					}
+
+					for _, data := range tile.Data {
+						// only export what the API describes as readable
+						if !strings.Contains(data.UI.Access, "r") {
+							continue
+						}
+
+						if value, ok := homeValue(data); ok {
+							homeEndpointValueGauges.WithLabelValues(node.Label, node.Category, data.Name).Set(value)
+						} else if str, ok := data.Value.(string); ok {
+							homeEndpointInfoGauges.WithLabelValues(node.Label, node.Category, data.Name, str).Set(1)
+						}
+
+						switch data.Name {
+						case "battery":
+							if value, ok := homeValue(data); ok {
+								homeNodeBatteryGauges.WithLabelValues(node.Label, node.Category).Set(value)
+							}
+						case "trigger":
+							if value, ok := homeValue(data); ok {
+								if previous, seen := homeTriggers[node.ID]; seen && previous != value {
+									homeLastTriggers[node.ID] = time.Now()
+								}
+								homeTriggers[node.ID] = value
+								if last, ok := homeLastTriggers[node.ID]; ok {
+									homeNodeLastTriggerGauges.WithLabelValues(node.Label, node.Category).Set(float64(last.Unix()))
+								}
+							}
+						case "state":
+							if node.Category == "alarm" {
+								state, _ := data.Value.(string)
+								armed := strings.HasSuffix(state, "_armed") || state == "alert"
+								homeAlarmArmedGauges.WithLabelValues(node.Label).Set(bool2float(armed))
+							}
+						}
+					}
+				}
+			}
+
+			// PVR metrics
+			if pvr {
+				pvrProgrammedStats, err := getPvrRecords(myAuthInfo, myPvrProgrammedRequest, &mySessionToken)
+				if err != nil {
+					log.Printf("An error occured with PVR programmed metrics: %v", err)
+				}
+				if pvrProgrammedStats.Success {
+					pvrProgrammedGauges.Reset()
+					for state, count := range pvrProgrammedStats.countByState() {
+						pvrProgrammedGauges.WithLabelValues(state).Set(float64(count))
+					}
+				}
+
+				pvrFinishedStats, err := getPvrRecords(myAuthInfo, myPvrFinishedRequest, &mySessionToken)
+				if err != nil {
+					log.Printf("An error occured with PVR finished metrics: %v", err)
+				}
+				if pvrFinishedStats.Success {
+					pvrFinishedGauges.Reset()
+					for state, count := range pvrFinishedStats.countByState() {
+						pvrFinishedGauges.WithLabelValues(state).Set(float64(count))
+					}
+				}
+
+				pvrConfigStats, err := getPvrConfig(myAuthInfo, myPvrConfigRequest, &mySessionToken)
+				if err != nil {
+					log.Printf("An error occured with PVR config metrics: %v", err)
+				}
+				if pvrConfigStats.Success {
+					pvrDiskGauges.WithLabelValues("free").Set(float64(pvrConfigStats.Result.FreeBytes))
+					pvrDiskGauges.WithLabelValues("total").Set(float64(pvrConfigStats.Result.TotalBytes))
+				}
+			}
+
+			// parental control metrics
+			if parental {
+				profilesStats, err := getProfiles(myAuthInfo, myProfileRequest, &mySessionToken)
+				if err != nil {
+					log.Printf("An error occured with profile metrics: %v", err)
+				}
+				profileNames := make(map[int]string)
+				for _, p := range profilesStats.Result {
+					profileNames[p.ID] = p.Name
+				}
+
+				networkControlsStats, err := getNetworkControls(myAuthInfo, myNetworkControlRequest, &mySessionToken)
+				if err != nil {
+					log.Printf("An error occured with network control metrics: %v", err)
+				}
+				if networkControlsStats.Success {
+					parentalModeGauges.Reset()
+					parentalOverrideGauges.Reset()
+					parentalHostsGauges.Reset()
+					parentalNextChangeGauges.Reset()
+				}
+				for _, control := range networkControlsStats.Result {
+					name, ok := profileNames[control.ProfileID]
+					if !ok {
+						name = strconv.Itoa(control.ProfileID)
+					}
+
+					for _, mode := range []string{"allowed", "denied", "webonly"} {
+						parentalModeGauges.WithLabelValues(name, mode).Set(bool2float(control.CurrentMode == mode))
+					}
+					parentalOverrideGauges.WithLabelValues(name).Set(bool2float(control.overridden(time.Now())))
+					parentalHostsGauges.WithLabelValues(name).Set(float64(len(control.Macs)))
+					parentalNextChangeGauges.WithLabelValues(name).Set(float64(control.NextChange))
+				}
+			}
+
+			// player metrics
+			if freeboxPlayer {
+				playersStats, err := getPlayers(myAuthInfo, myPlayerRequest, &mySessionToken)
+				if err != nil {
+					log.Printf("An error occured with player metrics: %v", err)
+				}
+				if playersStats.Success {
+					playerInfoGauges.Reset()
+					playerReachableGauges.Reset()
+					playerPowerStateGauges.Reset()
+					playerUptimeGauges.Reset()
+					playerVolumeGauges.Reset()
+					playerForegroundAppGauges.Reset()
+				}
+				for _, p := range playersStats.Result {
+					playerInfoGauges.WithLabelValues(p.DeviceName, p.DeviceModel, p.Mac).Set(1)
+					playerReachableGauges.WithLabelValues(p.DeviceName).Set(bool2float(p.Reachable))
+
+					if !p.statusAvailable() {
+						continue
+					}
+
+					myPlayerStatusRequest := &postRequest{
+						method: "GET",
+						url:    playerStatusURL(mafreebox, p.ID),
+						header: "X-Fbx-App-Auth",
+					}
+					playerStatusStats, err := getPlayerStatus(myAuthInfo, myPlayerStatusRequest, &mySessionToken)
+					if err != nil {
+						log.Printf("An error occured with player status metrics: %v", err)
+						continue
+					}
+					if !playerStatusStats.Success {
+						continue
+					}
+
+					status := playerStatusStats.Result
+					playerPowerStateGauges.WithLabelValues(p.DeviceName, status.PowerState).Set(1)
+					playerUptimeGauges.WithLabelValues(p.DeviceName).Set(float64(status.Uptime))
+					playerVolumeGauges.WithLabelValues(p.DeviceName).Set(float64(status.Volume))
+					if status.ForegroundApp.Package != "" {
+						playerForegroundAppGauges.WithLabelValues(p.DeviceName, status.ForegroundApp.Package).Set(1)
+					}
 				}
 			}
 
@@ -361,9 +1402,141 @@ func main() {
 			if err != nil {
 				log.Printf("An error occured with VPN station metrics: %v", err)
 			}
+			if getVpnServerResult.Success {
+				vpnServerConnectionsList.Reset()
+				vpnServerSessionAuthenticatedGauges.Reset()
+				vpnServerSessionConnectedSinceGauges.Reset()
+			}
 			for _, connection := range getVpnServerResult.Result {
+				if !myLimiter.admit("vpn", "", connection.User) {
+					continue
+				}
 				vpnServerConnectionsList.With(prometheus.Labels{"user": connection.User, "vpn": connection.Vpn, "src_ip": connection.SrcIP, "local_ip": connection.LocalIP, "name": "rx_bytes"}).Set(float64(connection.RxBytes))
 				vpnServerConnectionsList.With(prometheus.Labels{"user": connection.User, "vpn": connection.Vpn, "src_ip": connection.SrcIP, "local_ip": connection.LocalIP, "name": "tx_bytes"}).Set(float64(connection.TxBytes))
+
+				labels := prometheus.Labels{"id": connection.ID, "user": connection.User, "vpn": connection.Vpn, "src_ip": connection.SrcIP, "local_ip": connection.LocalIP}
+				vpnServerSessionAuthenticatedGauges.With(labels).Set(bool2float(connection.Authenticated))
+				if connection.AuthTime > 0 {
+					vpnServerSessionConnectedSinceGauges.With(labels).Set(float64(connection.AuthTime))
+				}
+			}
+
+			// VPN servers
+			vpnServerConfigResult, err := getVpnServerConfig(myAuthInfo, myVpnServerConfigRequest, &mySessionToken)
+			if err != nil {
+				log.Printf("An error occured with VPN server metrics: %v", err)
+			}
+			if vpnServerConfigResult.Success {
+				vpnServerStateGauges.Reset()
+				vpnServerSessionsGauges.Reset()
+			}
+			for _, server := range vpnServerConfigResult.Result {
+				vpnServerStateGauges.WithLabelValues(server.Name, server.Type, server.State).Set(1)
+				vpnServerSessionsGauges.WithLabelValues(server.Name, server.Type, "true").Set(float64(server.AuthConnectionCount))
+				vpnServerSessionsGauges.WithLabelValues(server.Name, server.Type, "false").Set(float64(server.ConnectionCount - server.AuthConnectionCount))
+			}
+
+			// VPN client
+			vpnClientResult, err := getVpnClientStatus(myAuthInfo, myVpnClientStatusRequest, &mySessionToken)
+			if err != nil {
+				log.Printf("An error occured with VPN client metrics: %v", err)
+			}
+			if vpnClientResult.Success {
+				result := vpnClientResult.Result
+				vpnClientEnabledGauge.Set(bool2float(result.Enabled))
+				vpnClientStateGauges.Reset()
+				for _, state := range []string{"going_up", "up", "going_down", "down"} {
+					vpnClientStateGauges.WithLabelValues(result.ActiveVpnDescription, result.Type, state).Set(bool2float(result.State == state))
+				}
+				vpnClientLastUpGauge.Set(float64(result.LastUp))
+				vpnClientBytesGauges.WithLabelValues("up").Set(float64(result.Stats.BytesUp))
+				vpnClientBytesGauges.WithLabelValues("down").Set(float64(result.Stats.BytesDown))
+				vpnClientRateGauges.WithLabelValues("up").Set(float64(result.Stats.RateUp))
+				vpnClientRateGauges.WithLabelValues("down").Set(float64(result.Stats.RateDown))
+			}
+
+			// file sharing services metrics
+			sambaStats, err := getSambaConfig(myAuthInfo, mySambaRequest, &mySessionToken)
+			if err != nil {
+				log.Printf("An error occured with samba metrics: %v", err)
+			}
+			if sambaStats.Success {
+				serviceEnabledGauges.WithLabelValues("samba_file").Set(bool2float(sambaStats.Result.FileShareEnabled))
+				serviceEnabledGauges.WithLabelValues("samba_print").Set(bool2float(sambaStats.Result.PrintShareEnabled))
+			}
+
+			afpStats, err := getAfpConfig(myAuthInfo, myAfpRequest, &mySessionToken)
+			if err != nil {
+				log.Printf("An error occured with AFP metrics: %v", err)
+			}
+			if afpStats.Success {
+				serviceEnabledGauges.WithLabelValues("afp").Set(bool2float(afpStats.Result.Enabled))
+				serviceGuestAccessGauges.WithLabelValues("afp").Set(bool2float(afpStats.Result.GuestAllow))
+			}
+
+			ftpStats, err := getFtpConfig(myAuthInfo, myFtpRequest, &mySessionToken)
+			if err != nil {
+				log.Printf("An error occured with FTP metrics: %v", err)
+			}
+			if ftpStats.Success {
+				serviceEnabledGauges.WithLabelValues("ftp").Set(bool2float(ftpStats.Result.Enabled))
+				serviceGuestAccessGauges.WithLabelValues("ftp").Set(bool2float(ftpStats.Result.AllowAnonymous))
+				ftpAnonymousWriteGauge.Set(bool2float(ftpStats.Result.AllowAnonymousWrite))
+				ftpRemoteAccessGauge.Set(bool2float(ftpStats.Result.AllowRemoteAccess))
+			}
+
+			for service, pr := range map[string]*postRequest{"upnpav": myUpnpavRequest, "airmedia": myAirmediaRequest} {
+				serviceStats, err := getServiceConfig(myAuthInfo, pr, &mySessionToken)
+				if err != nil {
+					log.Printf("An error occured with %s metrics: %v", service, err)
+				}
+				if serviceStats.Success {
+					serviceEnabledGauges.WithLabelValues(service).Set(bool2float(serviceStats.Result.Enabled))
+				}
+			}
+
+			// port forwarding metrics
+			fwRedirStats, err := getFwRedirs(myAuthInfo, myFwRedirRequest, &mySessionToken)
+			if err != nil {
+				log.Printf("An error occured with port forwarding metrics: %v", err)
+			}
+			upnpigdRedirStats, err := getUpnpigdRedirs(myAuthInfo, myUpnpigdRedirRequest, &mySessionToken)
+			if err != nil {
+				log.Printf("An error occured with UPnP IGD metrics: %v", err)
+			}
+			if fwRedirStats.Success && upnpigdRedirStats.Success {
+				portForwardingGauges.Reset()
+			}
+			for _, redir := range fwRedirStats.Result {
+				if !redir.Enabled {
+					continue
+				}
+				portForwardingGauges.WithLabelValues(redir.IPProto, strconv.Itoa(redir.WanPortStart), strconv.Itoa(redir.WanPortEnd), redir.LanIP, strconv.Itoa(redir.LanPort), "false").Set(1)
+			}
+
+			if upnpigdRedirStats.Success {
+				for _, redir := range upnpigdRedirStats.Result {
+					if !redir.Enabled {
+						continue
+					}
+					wanPort := strconv.Itoa(redir.WanPort)
+					portForwardingGauges.WithLabelValues(redir.IPProto, wanPort, wanPort, redir.LanIP, strconv.Itoa(redir.LanPort), "true").Set(1)
+				}
+
+				// the first poll only sets the baseline
+				upnpigdRedirs, created := upnpigdRedirStats.created(knownUpnpigdRedirs)
+				upnpigdRedirectionsCreatedCounter.Add(float64(created))
+				knownUpnpigdRedirs = upnpigdRedirs
+				upnpigdRedirectionsGauge.Set(float64(len(upnpigdRedirs)))
+			}
+
+			fwDmzStats, err := getFwDmz(myAuthInfo, myFwDmzRequest, &mySessionToken)
+			if err != nil {
+				log.Printf("An error occured with DMZ metrics: %v", err)
+			}
+			if fwDmzStats.Success {
+				dmzEnabledGauges.Reset()
+				dmzEnabledGauges.WithLabelValues(fwDmzStats.Result.IP).Set(bool2float(fwDmzStats.Result.Enabled))
 			}
 
 			// Switch status
@@ -371,8 +1544,30 @@ func main() {
 			if err != nil {
 				log.Printf("An error occured with switch metrics: %v", err)
 			}
+			if switchStats.Success {
+				switchPortMacInfoGauges.Reset()
+			}
 			for _, port := range switchStats.Result {
-				if port.Link == "up" {
+				switchPortLinkUpGauges.WithLabelValues(port.Name).Set(bool2float(port.Link == "up"))
+				switchPortFullDuplexGauges.WithLabelValues(port.Name).Set(bool2float(port.Duplex == "full"))
+				switchPortMacsGauges.WithLabelValues(port.Name).Set(float64(len(port.MacList)))
+				if speed, err := strconv.Atoi(port.Speed); err == nil {
+					switchPortSpeedGauges.WithLabelValues(port.Name).Set(float64(speed) * 1e6)
+				}
+				if switchMacInfo {
+					for _, mac := range port.MacList {
+						mac.Hostname = hostRelabel.name(mac.Mac, mac.Hostname)
+						if !myLimiter.admit("switch", mac.Mac, mac.Hostname) {
+							continue
+						}
+						switchPortMacInfoGauges.WithLabelValues(port.Name, mac.Mac, mac.Hostname).Set(1)
+					}
+				}
+
+				// ports going down must not keep their last stats
+				if port.Link != "up" {
+					deleteSwitchPortStats(port.Name)
+				} else {
 					mySwitchPortRequest := &postRequest{
 						method: "GET",
 						url:    mafreebox + "api/v8/switch/port/" + strconv.Itoa(port.ID) + "/stats",
@@ -381,6 +1576,10 @@ func main() {
 					switchPortStats, err := getSwitchPort(myAuthInfo, mySwitchPortRequest, &mySessionToken)
 					if err != nil {
 						log.Printf("An error occured with switch port metrics: %v", err)
+						continue
+					}
+					if !switchPortStats.Success {
+						continue
 					}
 
 					switchPortPacketsGauges.With(prometheus.Labels{"name": port.Name, "direction": "rx", "type": "broadcast", "error": "0"}).Set(float64(switchPortStats.Result.RxBroadcastPackets))
@@ -421,13 +1620,25 @@ func main() {
 				}
 			}
 
+			myLimiter.publish()
+			pruneEventHosts(myLimiter)
+
 			time.Sleep(10 * time.Second)
 		}
 	}()
 
+	if events {
+		go watchEvents(eventsURL(mafreebox), "X-Fbx-App-Auth", myLimiter)
+	}
+
 	log.Println("freebox_exporter started on port", listen)
-	http.Handle("/metrics", promhttp.Handler())
-	log.Fatal(http.ListenAndServe(listen, nil))
+	if hostRelabel == nil {
+		http.Handle("/metrics", promhttp.Handler())
+	} else {
+		gatherer := relabelGatherer{Gatherer: prometheus.DefaultGatherer, config: hostRelabel}
+		http.Handle("/metrics", promhttp.InstrumentMetricHandler(prometheus.DefaultRegisterer, promhttp.HandlerFor(gatherer, promhttp.HandlerOpts{})))
+	}
+	log.Fatal(listenAndServe(listen, webConfigFile))
 }
 
 func logFields(result interface{}, gauge *prometheus.GaugeVec, fields []string) error {
@@ -451,9 +1662,80 @@ func logFields(result interface{}, gauge *prometheus.GaugeVec, fields []string)
 	return nil
 }
 
+// deleteSwitchPortStats removes the stats series of a switch port
+func deleteSwitchPortStats(name string) {
+	for direction, types := range map[string][]string{
+		"rx": {"broadcast", "multicast", "unicast"},
+		"tx": {"broadcast", "multicast", "unicast"},
+	} {
+		for _, t := range types {
+			switchPortPacketsGauges.Delete(prometheus.Labels{"name": name, "direction": direction, "type": t, "error": "0"})
+		}
+	}
+	for direction, types := range map[string][]string{
+		"rx": {"err", "fcs", "fragment", "jabber", "oversize", "undersize"},
+		"tx": {"collision", "deferred", "excessive", "fcs", "late", "multiple", "single"},
+	} {
+		for _, t := range types {
+			switchPortPacketsGauges.Delete(prometheus.Labels{"name": name, "direction": direction, "type": t, "error": "1"})
+		}
+	}
+	for direction, types := range map[string][]string{
+		"rx": {"bad", "good"},
+		"tx": {"total"},
+	} {
+		for _, t := range types {
+			switchPortBytesGauges.Delete(prometheus.Labels{"name": name, "direction": direction, "type": t})
+		}
+	}
+	for _, direction := range []string{"rx", "tx"} {
+		labels := prometheus.Labels{"name": name, "direction": direction}
+		switchPortPacketsTotalGauges.Delete(labels)
+		switchPortPauseGauges.Delete(labels)
+		switchPortPacketsRateGauges.Delete(labels)
+		switchPortBytesRateGauges.Delete(labels)
+	}
+}
+
+// playerStatusURL returns the status API of a player, proxied by the
+// Freebox Server
+func playerStatusURL(endpoint string, id int) string {
+	return endpoint + "api/v6/player/" + strconv.Itoa(id) + "/api/v6/status/"
+}
+
+// lteUnsupported tells if the LTE endpoint error means the box has no
+// 4G aggregation, rather than a transient failure
+func lteUnsupported(err error) bool {
+	return err == errNotFound || err == apiErrors["nodev"] || err == apiErrors["invalid_request"]
+}
+
 func bool2float(b bool) float64 {
 	if b {
 		return 1
 	}
 	return 0
 }
+
+// freeplugRole spells out the HomePlug role of a freeplug
+func freeplugRole(netRole string) string {
+	switch netRole {
+	case "cco":
+		return "coordinator"
+	case "pco":
+		return "proxy_coordinator"
+	case "sta":
+		return "station"
+	}
+	return netRole
+}
+
+// homeValue converts a numeric or boolean home endpoint value to float64
+func homeValue(data homeTileData) (float64, bool) {
+	switch value := data.Value.(type) {
+	case float64:
+		return value, true
+	case bool:
+		return bool2float(value), true
+	}
+	return 0, false
+}
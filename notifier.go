@@ -0,0 +1,166 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// notification is the JSON payload posted to the webhook
+type notification struct {
+	Event    string    `json:"event"`
+	Key      string    `json:"key,omitempty"`
+	Previous string    `json:"previous,omitempty"`
+	Current  string    `json:"current"`
+	Time     time.Time `json:"time"`
+}
+
+// watchedValue is the state of a value between two polls: a new value
+// only becomes current once it has been stable for the debounce delay
+type watchedValue struct {
+	current   string
+	candidate string
+	since     time.Time
+}
+
+type notifier struct {
+	url      string
+	debounce time.Duration
+	events   map[string]bool
+	client   *http.Client
+
+	mu     sync.Mutex
+	values map[string]*watchedValue
+	hosts  map[string]bool
+	wg     sync.WaitGroup
+}
+
+// notifierEvents are the transitions a webhook can be notified of
+var notifierEvents = []string{"ftth_link", "xdsl_status", "disk_status", "new_host"}
+
+// newNotifier returns nil when no webhook is configured, all the
+// methods of a nil notifier do nothing
+func newNotifier(url string, debounce time.Duration, events string) (*notifier, error) {
+	watched := make(map[string]bool)
+	for _, event := range strings.Split(events, ",") {
+		if event = strings.TrimSpace(event); event == "" {
+			continue
+		}
+		if !isNotifierEvent(event) {
+			return nil, fmt.Errorf("invalid webhook event %q", event)
+		}
+		watched[event] = true
+	}
+
+	if url == "" {
+		return nil, nil
+	}
+
+	return &notifier{
+		url:      url,
+		debounce: debounce,
+		events:   watched,
+		client:   &http.Client{Timeout: 10 * time.Second},
+		values:   make(map[string]*watchedValue),
+	}, nil
+}
+
+func isNotifierEvent(event string) bool {
+	for _, e := range notifierEvents {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// observe records the value of a watched event and posts a notification
+// when it changed and stayed changed for the debounce delay
+func (n *notifier) observe(event, value string) {
+	if n == nil || !n.events[event] {
+		return
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	now := time.Now()
+	v, ok := n.values[event]
+	if !ok {
+		// the first value is the baseline
+		n.values[event] = &watchedValue{current: value}
+		return
+	}
+
+	if value == v.current {
+		v.candidate = ""
+		return
+	}
+	if value != v.candidate {
+		v.candidate = value
+		v.since = now
+	}
+	if now.Sub(v.since) < n.debounce {
+		return
+	}
+
+	n.send(notification{Event: event, Previous: v.current, Current: value, Time: now})
+	v.current = value
+	v.candidate = ""
+}
+
+// observeHosts posts a notification for each MAC address never seen before,
+// the first list only sets the baseline
+func (n *notifier) observeHosts(hosts map[string]string) {
+	if n == nil || !n.events["new_host"] {
+		return
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.hosts == nil {
+		n.hosts = make(map[string]bool)
+		for mac := range hosts {
+			n.hosts[mac] = true
+		}
+		return
+	}
+
+	for mac, name := range hosts {
+		if n.hosts[mac] {
+			continue
+		}
+		n.hosts[mac] = true
+		n.send(notification{Event: "new_host", Key: mac, Current: name, Time: time.Now()})
+	}
+}
+
+// send posts the notification in the background so that a slow
+// webhook does not delay the metrics
+func (n *notifier) send(payload notification) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		log.Printf("An error occured with the webhook: %v", err)
+		return
+	}
+
+	n.wg.Add(1)
+	go func() {
+		defer n.wg.Done()
+		resp, err := n.client.Post(n.url, "application/json", bytes.NewReader(body))
+		if err != nil {
+			log.Printf("An error occured with the webhook: %v", err)
+			return
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode >= 300 {
+			log.Printf("An error occured with the webhook: %s", resp.Status)
+		}
+	}()
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestNotifier(t *testing.T) {
+	var mu sync.Mutex
+	var received []notification
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload := notification{}
+		json.NewDecoder(r.Body).Decode(&payload)
+		mu.Lock()
+		received = append(received, payload)
+		mu.Unlock()
+	}))
+	defer ts.Close()
+
+	n, err := newNotifier(ts.URL, 0, "ftth_link,new_host")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	n.observe("ftth_link", "true")
+	n.observe("ftth_link", "true")
+	n.observe("ftth_link", "false")
+	n.observe("xdsl_status", "showtime")
+	n.observe("xdsl_status", "down")
+
+	n.observeHosts(map[string]string{"AA:BB:CC:DD:EE:FF": "laptop"})
+	n.observeHosts(map[string]string{"AA:BB:CC:DD:EE:FF": "laptop", "11:22:33:44:55:66": "stranger"})
+	n.wg.Wait()
+
+	if len(received) != 2 {
+		t.Fatal("Expected 2, but got", len(received))
+	}
+
+	for _, payload := range received {
+		switch payload.Event {
+		case "ftth_link":
+			if payload.Previous != "true" || payload.Current != "false" {
+				t.Errorf("Expected true -> false, but got %s -> %s", payload.Previous, payload.Current)
+			}
+		case "new_host":
+			if payload.Key != "11:22:33:44:55:66" || payload.Current != "stranger" {
+				t.Errorf("Expected 11:22:33:44:55:66 stranger, but got %s %s", payload.Key, payload.Current)
+			}
+		default:
+			t.Error("Expected ftth_link or new_host, but got", payload.Event)
+		}
+	}
+}
+
+func TestNotifierDebounce(t *testing.T) {
+	n, err := newNotifier("http://127.0.0.1:0/", time.Hour, "disk_status")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	n.observe("disk_status", "active")
+	n.observe("disk_status", "error")
+	n.observe("disk_status", "active")
+
+	if n.values["disk_status"].current != "active" {
+		t.Error("Expected active, but got", n.values["disk_status"].current)
+	}
+
+	if n.values["disk_status"].candidate != "" {
+		t.Error("Expected no pending state, but got", n.values["disk_status"].candidate)
+	}
+
+	var disabled *notifier
+	disabled.observe("disk_status", "error")
+	if n, _ := newNotifier("", time.Minute, "disk_status"); n != nil {
+		t.Error("Expected no notifier without webhook url")
+	}
+
+	if _, err := newNotifier("http://127.0.0.1:0/", time.Minute, "ftth-link"); err == nil {
+		t.Error("Expected an error on an unknown event")
+	}
+}
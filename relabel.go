@@ -0,0 +1,175 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/model"
+)
+
+// hostRelabel holds the relabel rules, nil when none are configured
+var hostRelabel *relabelConfig
+
+// hostMetricFamilies are the per host metrics dropped and static labels
+// apply to
+var hostMetricFamilies = map[string]bool{
+	"freebox_lan_reachable":                         true,
+	"freebox_lan_host_events_total":                 true,
+	"freebox_lan_host_last_seen_timestamp_seconds":  true,
+	"freebox_lan_host_known":                        true,
+	"freebox_lan_host_inventory_info":               true,
+	"freebox_lan_host_first_seen_timestamp_seconds": true,
+	"freebox_wifi_signal_attenuation_db":            true,
+	"freebox_wifi_inactive_duration_seconds":        true,
+	"freebox_wifi_connection_duration_seconds":      true,
+	"freebox_wifi_rx_bytes":                         true,
+	"freebox_wifi_tx_bytes":                         true,
+	"freebox_wifi_rx_rate":                          true,
+	"freebox_wifi_tx_rate":                          true,
+	"freebox_wifi_station_state":                    true,
+	"freebox_wifi_station_info":                     true,
+	"freebox_wifi_station_flag":                     true,
+	"freebox_wifi_station_phy_rate_bits":            true,
+	"freebox_wifi_station_mcs":                      true,
+	"freebox_wifi_station_nss":                      true,
+	"freebox_wifi_station_bandwidth_mhz":            true,
+	"freebox_switch_port_mac_info":                  true,
+}
+
+// relabelRewrite replaces the hostnames matching Regex, ${mac} in
+// Replacement expands to the MAC address of the host
+type relabelRewrite struct {
+	Regex       string `json:"regex"`
+	Replacement string `json:"replacement"`
+
+	re *regexp.Regexp
+}
+
+// relabelConfig describes how host labels are rewritten
+type relabelConfig struct {
+	Names    map[string]string `json:"names"`
+	Rewrites []relabelRewrite  `json:"rewrites"`
+	Drop     []string          `json:"drop"`
+	Static   map[string]string `json:"static"`
+}
+
+// loadRelabelConfig reads the relabel rules from a JSON file
+func loadRelabelConfig(location string) (*relabelConfig, error) {
+	data, err := ioutil.ReadFile(location)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &relabelConfig{}
+	err = json.Unmarshal(data, c)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]string)
+	for mac, name := range c.Names {
+		names[strings.ToUpper(mac)] = name
+	}
+	c.Names = names
+
+	for _, label := range c.Drop {
+		if !validLabelName(label) {
+			return nil, fmt.Errorf("invalid label name %q in drop", label)
+		}
+	}
+	for label := range c.Static {
+		if !validLabelName(label) {
+			return nil, fmt.Errorf("invalid label name %q in static", label)
+		}
+	}
+
+	for i := range c.Rewrites {
+		c.Rewrites[i].re, err = regexp.Compile(c.Rewrites[i].Regex)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return c, nil
+}
+
+// validLabelName rejects invalid and reserved label names
+func validLabelName(label string) bool {
+	return model.LabelName(label).IsValid() && !strings.HasPrefix(label, "__")
+}
+
+// name returns the hostname to export for a host, the MAC mapping wins
+// over the rewrites which are applied in order
+func (c *relabelConfig) name(mac, name string) string {
+	if c == nil {
+		return name
+	}
+	if friendly, ok := c.Names[strings.ToUpper(mac)]; ok {
+		return friendly
+	}
+	for _, rewrite := range c.Rewrites {
+		replacement := strings.Replace(rewrite.Replacement, "${mac}", mac, -1)
+		name = rewrite.re.ReplaceAllString(name, replacement)
+	}
+	return name
+}
+
+// relabelGatherer drops and adds labels on the gathered host metrics
+type relabelGatherer struct {
+	prometheus.Gatherer
+	config *relabelConfig
+}
+
+// Gather implements prometheus.Gatherer, series made identical by
+// dropped labels are only exported once and counted as collapsed
+func (g relabelGatherer) Gather() ([]*dto.MetricFamily, error) {
+	families, err := g.Gatherer.Gather()
+
+	drop := make(map[string]bool)
+	for _, label := range g.config.Drop {
+		drop[label] = true
+	}
+
+	for _, family := range families {
+		if !hostMetricFamilies[family.GetName()] {
+			continue
+		}
+
+		seen := make(map[string]bool)
+		metrics := family.Metric[:0]
+		for _, metric := range family.Metric {
+			labels := metric.Label[:0]
+			for _, label := range metric.Label {
+				if _, static := g.config.Static[label.GetName()]; !static && !drop[label.GetName()] {
+					labels = append(labels, label)
+				}
+			}
+			for name, value := range g.config.Static {
+				labels = append(labels, &dto.LabelPair{Name: proto.String(name), Value: proto.String(value)})
+			}
+			sort.Slice(labels, func(i, j int) bool { return labels[i].GetName() < labels[j].GetName() })
+			metric.Label = labels
+
+			key := ""
+			for _, label := range labels {
+				key += label.GetName() + "\xff" + label.GetValue() + "\xff"
+			}
+			if seen[key] {
+				relabelCollapsedCounter.WithLabelValues(family.GetName()).Inc()
+				continue
+			}
+			seen[key] = true
+			metrics = append(metrics, metric)
+		}
+		family.Metric = metrics
+	}
+
+	return families, err
+}
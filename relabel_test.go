@@ -0,0 +1,90 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestRelabel(t *testing.T) {
+	dir, err := ioutil.TempDir("", "relabel")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	location := filepath.Join(dir, "relabel.json")
+	err = ioutil.WriteFile(location, []byte(`{
+		"names": {"aa:bb:cc:dd:ee:ff": "alice-phone"},
+		"rewrites": [{"regex": "^(iPhone)?$", "replacement": "unknown-${mac}"}],
+		"drop": ["ip"],
+		"static": {"site": "home"}
+	}`), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := loadRelabelConfig(location)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct{ mac, name, expected string }{
+		{"AA:BB:CC:DD:EE:FF", "iPhone", "alice-phone"},
+		{"11:22:33:44:55:66", "iPhone", "unknown-11:22:33:44:55:66"},
+		{"11:22:33:44:55:66", "", "unknown-11:22:33:44:55:66"},
+		{"11:22:33:44:55:66", "laptop", "laptop"},
+	} {
+		if name := c.name(tc.mac, tc.name); name != tc.expected {
+			t.Error("Expected", tc.expected, "but got", name)
+		}
+	}
+
+	var nilConfig *relabelConfig
+	if name := nilConfig.name("11:22:33:44:55:66", "iPhone"); name != "iPhone" {
+		t.Error("Expected iPhone, but got", name)
+	}
+
+	registry := prometheus.NewRegistry()
+	gauges := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "freebox_lan_reachable"}, []string{"mac", "ip"})
+	other := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "freebox_switch_port_macs"}, []string{"ip"})
+	registry.MustRegister(gauges, other)
+	gauges.WithLabelValues("11:22:33:44:55:66", "192.168.1.10").Set(1)
+	gauges.WithLabelValues("11:22:33:44:55:66", "192.168.1.11").Set(1)
+	other.WithLabelValues("192.168.1.10").Set(1)
+
+	families, err := relabelGatherer{Gatherer: registry, config: c}.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(families[0].Metric) != 1 {
+		t.Fatal("Expected 1, but got", len(families[0].Metric))
+	}
+	labels := families[0].Metric[0].Label
+	if len(labels) != 2 || labels[0].GetName() != "mac" || labels[1].GetName() != "site" || labels[1].GetValue() != "home" {
+		t.Error("Expected mac and site labels, but got", labels)
+	}
+	if value := testutil.ToFloat64(relabelCollapsedCounter.WithLabelValues("freebox_lan_reachable")); value != 1 {
+		t.Error("Expected 1, but got", value)
+	}
+
+	// only host metrics are relabeled
+	labels = families[1].Metric[0].Label
+	if len(labels) != 1 || labels[0].GetName() != "ip" {
+		t.Error("Expected the ip label only, but got", labels)
+	}
+
+	for _, config := range []string{`{"static": {"__name__": "foo"}}`, `{"static": {"my-site": "home"}}`, `{"drop": ["0ip"]}`} {
+		err = ioutil.WriteFile(location, []byte(config), 0600)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := loadRelabelConfig(location); err == nil {
+			t.Error("Expected an error on", config)
+		}
+	}
+}
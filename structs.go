@@ -1,6 +1,10 @@
 package main
 
-import "bufio"
+import (
+	"bufio"
+	"encoding/json"
+	"time"
+)
 
 type apiResponse struct {
 	Success   bool   `json:"success"`
@@ -69,6 +73,93 @@ type rrd struct {
 }
 
 // https://dev.freebox.fr/sdk/os/connection/
+type connection struct {
+	apiResponse
+	Result struct {
+		State         string `json:"state,omitempty"`
+		Type          string `json:"type,omitempty"`
+		Media         string `json:"media,omitempty"`
+		IPv4          string `json:"ipv4,omitempty"`
+		IPv6          string `json:"ipv6,omitempty"`
+		RateUp        int64  `json:"rate_up,omitempty"`
+		RateDown      int64  `json:"rate_down,omitempty"`
+		BandwidthUp   int64  `json:"bandwidth_up,omitempty"`
+		BandwidthDown int64  `json:"bandwidth_down,omitempty"`
+		BytesUp       int64  `json:"bytes_up,omitempty"`
+		BytesDown     int64  `json:"bytes_down,omitempty"`
+	} `json:"result"`
+}
+
+type connectionConfig struct {
+	apiResponse
+	Result struct {
+		Ping             bool   `json:"ping,omitempty"`
+		Wol              bool   `json:"wol,omitempty"`
+		Adblock          bool   `json:"adblock,omitempty"`
+		RemoteAccess     bool   `json:"remote_access,omitempty"`
+		RemoteAccessPort int    `json:"remote_access_port,omitempty"` // HTTP port
+		RemoteAccessIP   string `json:"remote_access_ip,omitempty"`
+		APIRemoteAccess  bool   `json:"api_remote_access,omitempty"`
+		HTTPSAvailable   bool   `json:"https_available,omitempty"`
+		HTTPSPort        int    `json:"https_port,omitempty"`
+	} `json:"result"`
+}
+
+type connectionDdnsStatus struct {
+	apiResponse
+	Result struct {
+		Status      string `json:"status,omitempty"`
+		LastRefresh int64  `json:"last_refresh,omitempty"`
+		NextRefresh int64  `json:"next_refresh,omitempty"`
+		LastError   int64  `json:"last_error,omitempty"`
+	} `json:"result"`
+}
+
+type connectionIPv6Config struct {
+	apiResponse
+	Result struct {
+		IPv6Enabled bool `json:"ipv6_enabled,omitempty"`
+		Delegations []struct {
+			Prefix  string `json:"prefix,omitempty"`
+			NextHop string `json:"next_hop,omitempty"`
+		} `json:"delegations,omitempty"`
+	} `json:"result"`
+}
+
+type lteTunnel struct {
+	Connected   bool  `json:"connected,omitempty"`
+	RxFlowsRate int64 `json:"rx_flows_rate,omitempty"`
+	TxFlowsRate int64 `json:"tx_flows_rate,omitempty"`
+	RxMaxRate   int64 `json:"rx_max_rate,omitempty"`
+	TxMaxRate   int64 `json:"tx_max_rate,omitempty"`
+}
+
+type connectionLte struct {
+	apiResponse
+	Result struct {
+		Enabled bool   `json:"enabled,omitempty"`
+		State   string `json:"state,omitempty"`
+		Radio   struct {
+			Associated  bool `json:"associated,omitempty"`
+			SignalLevel int  `json:"signal_level,omitempty"`
+			Bands       []struct {
+				Band      int  `json:"band,omitempty"`
+				Enabled   bool `json:"enabled,omitempty"`
+				Bandwidth int  `json:"bandwidth,omitempty"`
+				Pci       int  `json:"pci,omitempty"`
+				Rsrp      int  `json:"rsrp,omitempty"`
+				Rsrq      int  `json:"rsrq,omitempty"`
+				Rssi      int  `json:"rssi,omitempty"`
+				Sinr      int  `json:"sinr,omitempty"`
+			} `json:"bands,omitempty"`
+		} `json:"radio,omitempty"`
+		Tunnel struct {
+			Lte  lteTunnel `json:"lte,omitempty"`
+			Xdsl lteTunnel `json:"xdsl,omitempty"`
+		} `json:"tunnel,omitempty"`
+	} `json:"result"`
+}
+
 type connectionXdsl struct {
 	apiResponse
 	Result struct {
@@ -168,7 +259,7 @@ type freeplugMember struct {
 	EthFullDuplex bool   `json:"eth_full_duplex"`
 	HasNetwork    bool   `json:"has_network"`
 	EthSpeed      int    `json:"eth_speed"`
-	Inative       int    `json:"inactive"`
+	Inactive      int    `json:"inactive"`
 	NetID         string `json:"net_id"`
 	RxRate        int64  `json:"rx_rate"`
 	TxRate        int64  `json:"tx_rate"`
@@ -267,8 +358,22 @@ type systemV6 struct {
 
 // https://dev.freebox.fr/sdk/os/wifi/
 type wifiAccessPoint struct {
-	Name string `json:"name,omitempty"`
-	ID   int    `json:"id,omitempty"`
+	Name   string `json:"name,omitempty"`
+	ID     int    `json:"id,omitempty"`
+	Status struct {
+		State            string `json:"state,omitempty"`
+		ChannelWidth     string `json:"channel_width,omitempty"`
+		PrimaryChannel   int    `json:"primary_channel,omitempty"`
+		SecondaryChannel int    `json:"secondary_channel,omitempty"`
+	} `json:"status,omitempty"`
+	Config struct {
+		Band             string `json:"band,omitempty"`
+		ChannelWidth     string `json:"channel_width,omitempty"`
+		PrimaryChannel   int    `json:"primary_channel,omitempty"`
+		SecondaryChannel int    `json:"secondary_channel,omitempty"`
+	} `json:"config,omitempty"`
+	// capabilities are reported per band, e.g. {"5g": {"vht": true, ...}}
+	Capabilities map[string]map[string]interface{} `json:"capabilities,omitempty"`
 }
 
 type wifi struct {
@@ -276,10 +381,81 @@ type wifi struct {
 	Result []wifiAccessPoint `json:"result,omitempty"`
 }
 
+type wifiBss struct {
+	ID     string `json:"id,omitempty"`
+	PhyID  int    `json:"phy_id"`
+	Status struct {
+		State              string `json:"state,omitempty"`
+		StaCount           int    `json:"sta_count,omitempty"`
+		AuthorizedStaCount int    `json:"authorized_sta_count,omitempty"`
+		IsMainBss          bool   `json:"is_main_bss,omitempty"`
+	} `json:"status,omitempty"`
+	Config struct {
+		Enabled    bool   `json:"enabled,omitempty"`
+		Ssid       string `json:"ssid,omitempty"`
+		HideSsid   bool   `json:"hide_ssid,omitempty"`
+		Encryption string `json:"encryption,omitempty"`
+	} `json:"config,omitempty"`
+}
+
+type wifiBssList struct {
+	apiResponse
+	Result []wifiBss `json:"result,omitempty"`
+}
+
+type wifiConfig struct {
+	apiResponse
+	Result struct {
+		Enabled        bool   `json:"enabled,omitempty"`
+		MacFilterState string `json:"mac_filter_state,omitempty"`
+	} `json:"result,omitempty"`
+}
+
+type wifiChannelUsage struct {
+	Band          string `json:"band,omitempty"`
+	Channel       int    `json:"channel,omitempty"`
+	NoiseLevel    int    `json:"noise_level,omitempty"`
+	BusyPercent   int    `json:"busy_percent,omitempty"`
+	RxBusyPercent int    `json:"rx_busy_percent,omitempty"`
+	TxPercent     int    `json:"tx_percent,omitempty"`
+}
+
+type wifiChannelUsages struct {
+	apiResponse
+	Result []wifiChannelUsage `json:"result,omitempty"`
+}
+
+type wifiNeighbor struct {
+	Bssid            string `json:"bssid,omitempty"`
+	Ssid             string `json:"ssid,omitempty"`
+	Band             string `json:"band,omitempty"`
+	Channel          int    `json:"channel,omitempty"`
+	SecondaryChannel int    `json:"secondary_channel,omitempty"`
+	ChannelWidth     string `json:"channel_width,omitempty"`
+	Signal           int    `json:"signal,omitempty"`
+	LastSeenTs       int64  `json:"last_seen_ts,omitempty"`
+}
+
+type wifiNeighbors struct {
+	apiResponse
+	Result []wifiNeighbor `json:"result,omitempty"`
+}
+
+type wifiStationStats struct {
+	Bitrate int    `json:"bitrate,omitempty"` // in 100 kbit/s
+	Mcs     int    `json:"mcs,omitempty"`
+	VhtMcs  int    `json:"vht_mcs,omitempty"`
+	Nss     int    `json:"nss,omitempty"`
+	Width   string `json:"width,omitempty"`
+	ShortGI bool   `json:"shortgi,omitempty"`
+}
+
 type wifiStation struct {
 	Hostname           string `json:"hostname,omitempty"`
 	MAC                string `json:"mac,omitempty"`
+	Bssid              string `json:"bssid,omitempty"`
 	State              string `json:"state,omitempty"`
+	Standard           string `json:"standard,omitempty"`
 	Inactive           int    `json:"inactive,omitempty"`
 	RXBytes            int64  `json:"rx_bytes,omitempty"`
 	TXBytes            int64  `json:"tx_bytes,omitempty"`
@@ -287,6 +463,26 @@ type wifiStation struct {
 	TXRate             int64  `json:"tx_rate,omitempty"`
 	RXRate             int64  `json:"rx_rate,omitempty"`
 	Signal             int    `json:"signal,omitempty"`
+	Flags              struct {
+		Legacy     bool `json:"legacy,omitempty"`
+		Ht         bool `json:"ht,omitempty"`
+		Vht        bool `json:"vht,omitempty"`
+		He         bool `json:"he,omitempty"`
+		Authorized bool `json:"authorized,omitempty"`
+		Wmm        bool `json:"wmm,omitempty"`
+		PowerSave  bool `json:"power_save,omitempty"`
+	} `json:"flags,omitempty"`
+	LastRx wifiStationStats `json:"last_rx,omitempty"`
+	LastTx wifiStationStats `json:"last_tx,omitempty"`
+}
+
+// mcs returns the MCS index of the last frames, which is reported
+// in vht_mcs for VHT and HE stations
+func (s *wifiStation) mcs(stats wifiStationStats) int {
+	if s.Flags.Vht || s.Flags.He {
+		return stats.VhtMcs
+	}
+	return stats.Mcs
 }
 
 type wifiStations struct {
@@ -294,6 +490,305 @@ type wifiStations struct {
 	Result []wifiStation `json:"result,omitempty"`
 }
 
+// https://dev.freebox.fr/sdk/os/home/
+type homeUI struct {
+	Access  string `json:"access,omitempty"` // r|w|rw
+	Display string `json:"display,omitempty"`
+	Unit    string `json:"unit,omitempty"`
+}
+
+type homeNode struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name,omitempty"`
+	Label    string `json:"label,omitempty"`
+	Category string `json:"category,omitempty"`
+	Status   string `json:"status,omitempty"`
+	Props    struct {
+		Stream string `json:"Stream,omitempty"`
+	} `json:"props,omitempty"`
+}
+
+type homeNodes struct {
+	apiResponse
+	Result []homeNode `json:"result,omitempty"`
+}
+
+type homeTileData struct {
+	EpID      int         `json:"ep_id"`
+	Label     string      `json:"label,omitempty"`
+	Name      string      `json:"name,omitempty"`
+	Value     interface{} `json:"value,omitempty"`
+	ValueType string      `json:"value_type,omitempty"` // bool|int|float|string|void
+	UI        homeUI      `json:"ui,omitempty"`
+}
+
+type homeTile struct {
+	NodeID int            `json:"node_id"`
+	Label  string         `json:"label,omitempty"`
+	Type   string         `json:"type,omitempty"`
+	Data   []homeTileData `json:"data,omitempty"`
+}
+
+type homeTileset struct {
+	apiResponse
+	Result []homeTile `json:"result,omitempty"`
+}
+
+// https://dev.freebox.fr/sdk/os/pvr/
+type pvrRecord struct {
+	ID          int    `json:"id"`
+	State       string `json:"state,omitempty"`
+	Error       string `json:"error,omitempty"`
+	Name        string `json:"name,omitempty"`
+	ChannelName string `json:"channel_name,omitempty"`
+	Start       int64  `json:"start,omitempty"`
+	End         int64  `json:"end,omitempty"`
+}
+
+type pvrRecords struct {
+	apiResponse
+	Result []pvrRecord `json:"result,omitempty"`
+}
+
+// countByState returns the number of recordings in each state
+func (p *pvrRecords) countByState() map[string]int {
+	counts := make(map[string]int)
+	for _, record := range p.Result {
+		counts[record.State]++
+	}
+	return counts
+}
+
+type pvrConfig struct {
+	apiResponse
+	Result struct {
+		MarginBefore int    `json:"margin_before,omitempty"`
+		MarginAfter  int    `json:"margin_after,omitempty"`
+		Media        string `json:"media,omitempty"`
+		FreeBytes    int64  `json:"free_bytes,omitempty"`
+		TotalBytes   int64  `json:"total_bytes,omitempty"`
+	} `json:"result"`
+}
+
+// https://dev.freebox.fr/sdk/os/parental/
+type profile struct {
+	ID   int    `json:"id"`
+	Name string `json:"name,omitempty"`
+	Icon string `json:"icon,omitempty"`
+}
+
+type profiles struct {
+	apiResponse
+	Result []profile `json:"result,omitempty"`
+}
+
+type networkControl struct {
+	ProfileID     int      `json:"profile_id"`
+	CurrentMode   string   `json:"current_mode,omitempty"` // allowed|denied|webonly
+	RuleMode      string   `json:"rule_mode,omitempty"`
+	Override      bool     `json:"override,omitempty"`
+	OverrideMode  string   `json:"override_mode,omitempty"`
+	OverrideUntil int64    `json:"override_until,omitempty"`
+	NextChange    int64    `json:"next_change,omitempty"`
+	Macs          []string `json:"macs,omitempty"`
+}
+
+// overridden tells if the override is still running, an override
+// without end date lasts until it is removed
+func (n *networkControl) overridden(now time.Time) bool {
+	return n.Override && (n.OverrideUntil == 0 || n.OverrideUntil > now.Unix())
+}
+
+type networkControls struct {
+	apiResponse
+	Result []networkControl `json:"result,omitempty"`
+}
+
+// https://dev.freebox.fr/sdk/os/player/
+type player struct {
+	ID                int    `json:"id"`
+	DeviceName        string `json:"device_name,omitempty"`
+	DeviceModel       string `json:"device_model,omitempty"`
+	Mac               string `json:"mac,omitempty"`
+	Reachable         bool   `json:"reachable,omitempty"`
+	APIAvailable      bool   `json:"api_available,omitempty"`
+	LastTimeReachable int64  `json:"last_time_reachable,omitempty"`
+}
+
+// statusAvailable tells if the player serves its status API, only
+// reachable players do
+func (p *player) statusAvailable() bool {
+	return p.Reachable && p.APIAvailable
+}
+
+type players struct {
+	apiResponse
+	Result []player `json:"result,omitempty"`
+}
+
+type playerStatus struct {
+	apiResponse
+	Result struct {
+		PowerState    string `json:"power_state,omitempty"` // running|standby
+		Uptime        int    `json:"uptime,omitempty"`
+		Volume        int    `json:"volume,omitempty"`
+		Mute          bool   `json:"mute,omitempty"`
+		ForegroundApp struct {
+			Package string `json:"package,omitempty"`
+			Name    string `json:"name,omitempty"`
+		} `json:"foreground_app,omitempty"`
+	} `json:"result"`
+}
+
+// https://dev.freebox.fr/sdk/os/vm/
+type vm struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name,omitempty"`
+	Status   string `json:"status,omitempty"` // stopped|running|starting|stopping
+	OS       string `json:"os,omitempty"`
+	Vcpus    int    `json:"vcpus,omitempty"`
+	Memory   int64  `json:"memory,omitempty"` // in MB
+	DiskPath string `json:"disk_path,omitempty"`
+	DiskType string `json:"disk_type,omitempty"`
+}
+
+type vms struct {
+	apiResponse
+	Result []vm `json:"result,omitempty"`
+}
+
+type vmInfo struct {
+	apiResponse
+	Result struct {
+		TotalMemory int64 `json:"total_memory,omitempty"` // in MB
+		UsedMemory  int64 `json:"used_memory,omitempty"`  // in MB
+		TotalCpus   int   `json:"total_cpus,omitempty"`
+		UsedCpus    int   `json:"used_cpus,omitempty"`
+	} `json:"result"`
+}
+
+type vmDiskRequest struct {
+	DiskPath string `json:"disk_path"`
+}
+
+type vmDiskInfo struct {
+	apiResponse
+	Result struct {
+		Type        string `json:"type,omitempty"`
+		ActualSize  int64  `json:"actual_size,omitempty"`
+		VirtualSize int64  `json:"virtual_size,omitempty"`
+	} `json:"result"`
+}
+
+// https://dev.freebox.fr/sdk/os/network_share/
+type sambaConfig struct {
+	apiResponse
+	Result struct {
+		FileShareEnabled  bool   `json:"file_share_enabled,omitempty"`
+		PrintShareEnabled bool   `json:"print_share_enabled,omitempty"`
+		LogonEnabled      bool   `json:"logon_enabled,omitempty"`
+		Workgroup         string `json:"workgroup,omitempty"`
+	} `json:"result"`
+}
+
+type afpConfig struct {
+	apiResponse
+	Result struct {
+		Enabled    bool `json:"enabled,omitempty"`
+		GuestAllow bool `json:"guest_allow,omitempty"`
+	} `json:"result"`
+}
+
+// https://dev.freebox.fr/sdk/os/ftp/
+type ftpConfig struct {
+	apiResponse
+	Result struct {
+		Enabled             bool `json:"enabled,omitempty"`
+		AllowAnonymous      bool `json:"allow_anonymous,omitempty"`
+		AllowAnonymousWrite bool `json:"allow_anonymous_write,omitempty"`
+		AllowRemoteAccess   bool `json:"allow_remote_access,omitempty"`
+		WeakPassword        bool `json:"weak_password,omitempty"`
+		PortCtrl            int  `json:"port_ctrl,omitempty"`
+	} `json:"result"`
+}
+
+// https://dev.freebox.fr/sdk/os/upnpav/ and https://dev.freebox.fr/sdk/os/airmedia/
+type serviceConfig struct {
+	apiResponse
+	Result struct {
+		Enabled bool `json:"enabled,omitempty"`
+	} `json:"result"`
+}
+
+// https://dev.freebox.fr/sdk/os/phone/
+type phoneConfig struct {
+	apiResponse
+	Result struct {
+		NetworkIsUp      bool   `json:"network_is_up,omitempty"`
+		DectEcoMode      bool   `json:"dect_eco_mode,omitempty"`
+		DectPin          string `json:"dect_pin,omitempty"`
+		DectRingPattern  int    `json:"dect_ring_pattern,omitempty"`
+		DectRegistration bool   `json:"dect_registration,omitempty"`
+		DectNemoMode     bool   `json:"dect_nemo_mode,omitempty"`
+		DectEnabled      bool   `json:"dect_enabled,omitempty"`
+		DectRingOnOff    bool   `json:"dect_ring_on_off,omitempty"`
+	} `json:"result"`
+}
+
+// phone lists the phones known to the box, FXS ports and DECT handsets
+type phone struct {
+	ID        int    `json:"id"`
+	Type      string `json:"type,omitempty"` // fxs|dect
+	Vendor    string `json:"vendor,omitempty"`
+	IsRinging bool   `json:"is_ringing,omitempty"`
+}
+
+type phones struct {
+	apiResponse
+	Result []phone `json:"result,omitempty"`
+}
+
+// dectHandsets returns the number of registered DECT handsets
+func (p *phones) dectHandsets() int {
+	count := 0
+	for _, phone := range p.Result {
+		if phone.Type == "dect" {
+			count++
+		}
+	}
+	return count
+}
+
+// https://dev.freebox.fr/sdk/os/lcd/
+type lcdConfigResult struct {
+	Brightness         int    `json:"brightness,omitempty"`
+	Orientation        int    `json:"orientation,omitempty"`
+	OrientationForced  bool   `json:"orientation_forced,omitempty"`
+	HideWifiKey        bool   `json:"hide_wifi_key,omitempty"`
+	LedStripEnabled    bool   `json:"led_strip_enabled,omitempty"`
+	LedStripBrightness int    `json:"led_strip_brightness,omitempty"`
+	LedStripAnimation  string `json:"led_strip_animation,omitempty"`
+}
+
+type lcdConfig struct {
+	apiResponse
+	Result lcdConfigResult `json:"result"`
+}
+
+// https://dev.freebox.fr/sdk/os/ws/
+type wsRegister struct {
+	Action string   `json:"action"`
+	Events []string `json:"events"`
+}
+
+type wsEvent struct {
+	apiResponse
+	Action string          `json:"action,omitempty"`
+	Source string          `json:"source,omitempty"`
+	Event  string          `json:"event,omitempty"`
+	Result json.RawMessage `json:"result,omitempty"`
+}
+
 type app struct {
 	AppID      string `json:"app_id"`
 	AppName    string `json:"app_name"`
@@ -339,6 +834,98 @@ type vpnServer struct {
 	} `json:"result,omitempty"`
 }
 
+// https://dev.freebox.fr/sdk/os/fw/
+type fwRedir struct {
+	ID           int    `json:"id,omitempty"`
+	Enabled      bool   `json:"enabled,omitempty"`
+	IPProto      string `json:"ip_proto,omitempty"`
+	WanPortStart int    `json:"wan_port_start,omitempty"`
+	WanPortEnd   int    `json:"wan_port_end,omitempty"`
+	LanIP        string `json:"lan_ip,omitempty"`
+	LanPort      int    `json:"lan_port,omitempty"`
+	SrcIP        string `json:"src_ip,omitempty"`
+	Hostname     string `json:"hostname,omitempty"`
+	Comment      string `json:"comment,omitempty"`
+}
+
+type fwRedirs struct {
+	apiResponse
+	Result []fwRedir `json:"result,omitempty"`
+}
+
+type fwDmz struct {
+	apiResponse
+	Result struct {
+		Enabled bool   `json:"enabled,omitempty"`
+		IP      string `json:"ip,omitempty"`
+	} `json:"result,omitempty"`
+}
+
+// https://dev.freebox.fr/sdk/os/upnpigd/
+type upnpigdRedir struct {
+	ID         string `json:"id,omitempty"`
+	Enabled    bool   `json:"enabled,omitempty"`
+	IPProto    string `json:"ip_proto,omitempty"`
+	WanPort    int    `json:"wan_port,omitempty"`
+	LanIP      string `json:"lan_ip,omitempty"`
+	LanPort    int    `json:"lan_port,omitempty"`
+	RemoteHost string `json:"remote_host,omitempty"`
+	Desc       string `json:"desc,omitempty"`
+}
+
+type upnpigdRedirs struct {
+	apiResponse
+	Result []upnpigdRedir `json:"result,omitempty"`
+}
+
+// created returns the IDs of the enabled redirections and how many of
+// them are missing from known, a nil known only sets the baseline
+func (u *upnpigdRedirs) created(known map[string]bool) (map[string]bool, int) {
+	ids := make(map[string]bool)
+	created := 0
+	for _, redir := range u.Result {
+		if !redir.Enabled {
+			continue
+		}
+		ids[redir.ID] = true
+		if known != nil && !known[redir.ID] {
+			created++
+		}
+	}
+	return ids, created
+}
+
+type vpnServerConfig struct {
+	apiResponse
+	Result []struct {
+		Name                string `json:"name,omitempty"`
+		Type                string `json:"type,omitempty"`  // pptp|openvpn|ipsec|wireguard
+		State               string `json:"state,omitempty"` // stopped|starting|started|stopping|error
+		ConnectionCount     int    `json:"connection_count,omitempty"`
+		AuthConnectionCount int    `json:"auth_connection_count,omitempty"`
+	} `json:"result,omitempty"`
+}
+
+// https://dev.freebox.fr/sdk/os/vpn_client/
+type vpnClientStatus struct {
+	apiResponse
+	Result struct {
+		Enabled              bool   `json:"enabled,omitempty"`
+		ActiveVpn            string `json:"active_vpn,omitempty"`
+		ActiveVpnDescription string `json:"active_vpn_description,omitempty"`
+		Type                 string `json:"type,omitempty"`
+		State                string `json:"state,omitempty"` // going_up|up|going_down|down
+		LastUp               int64  `json:"last_up,omitempty"`
+		LastError            string `json:"last_error,omitempty"`
+		Stats                struct {
+			RateUp    int64 `json:"rate_up,omitempty"`
+			RateDown  int64 `json:"rate_down,omitempty"`
+			BytesUp   int64 `json:"bytes_up,omitempty"`
+			BytesDown int64 `json:"bytes_down,omitempty"`
+		} `json:"stats,omitempty"`
+	} `json:"result"`
+}
+
 type switchStatus struct {
 	apiResponse
 	Result []struct {
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"sync"
+
+	"golang.org/x/crypto/bcrypt"
+	"gopkg.in/yaml.v2"
+)
+
+// webConfig follows the exporter-toolkit web configuration file
+type webConfig struct {
+	TLSConfig tlsServerConfig   `yaml:"tls_server_config"`
+	Users     map[string]string `yaml:"basic_auth_users"`
+}
+
+type tlsServerConfig struct {
+	CertFile   string `yaml:"cert_file"`
+	KeyFile    string `yaml:"key_file"`
+	ClientAuth string `yaml:"client_auth_type"`
+	ClientCAs  string `yaml:"client_ca_file"`
+}
+
+var clientAuthTypes = map[string]tls.ClientAuthType{
+	"":                           tls.NoClientCert,
+	"NoClientCert":               tls.NoClientCert,
+	"RequestClientCert":          tls.RequestClientCert,
+	"RequireAnyClientCert":       tls.RequireAnyClientCert,
+	"VerifyClientCertIfGiven":    tls.VerifyClientCertIfGiven,
+	"RequireAndVerifyClientCert": tls.RequireAndVerifyClientCert,
+}
+
+// loadWebConfig reads the web configuration file
+func loadWebConfig(location string) (*webConfig, error) {
+	data, err := ioutil.ReadFile(location)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &webConfig{}
+	err = yaml.UnmarshalStrict(data, c)
+	if err != nil {
+		return nil, err
+	}
+
+	if (c.TLSConfig.CertFile == "") != (c.TLSConfig.KeyFile == "") {
+		return nil, errors.New("both cert_file and key_file must be set")
+	}
+	if _, ok := clientAuthTypes[c.TLSConfig.ClientAuth]; !ok {
+		return nil, fmt.Errorf("invalid client_auth_type %q", c.TLSConfig.ClientAuth)
+	}
+	if c.TLSConfig.ClientAuth == "RequireAndVerifyClientCert" && c.TLSConfig.ClientCAs == "" {
+		return nil, errors.New("client_ca_file must be set to verify client certificates")
+	}
+
+	return c, nil
+}
+
+// tlsConfig builds the TLS configuration, nil when TLS is disabled
+func (c *webConfig) tlsConfig() (*tls.Config, error) {
+	if c.TLSConfig.CertFile == "" {
+		return nil, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(c.TLSConfig.CertFile, c.TLSConfig.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{cert},
+		ClientAuth:   clientAuthTypes[c.TLSConfig.ClientAuth],
+	}
+
+	if c.TLSConfig.ClientCAs != "" {
+		data, err := ioutil.ReadFile(c.TLSConfig.ClientCAs)
+		if err != nil {
+			return nil, err
+		}
+		config.ClientCAs = x509.NewCertPool()
+		if !config.ClientCAs.AppendCertsFromPEM(data) {
+			return nil, errors.New("no certificate found in client_ca_file")
+		}
+	}
+
+	return config, nil
+}
+
+// dummyHashedPassword is compared for unknown users, so they take as long
+// to reject as a wrong password
+const dummyHashedPassword = "$2a$10$oengas4ERhh9.R6AJZAGSePE9DPNWan2.ghZ5Rph1LGwYKEUc9UbW"
+
+// webAuthHandler checks the basic auth users of the web configuration,
+// the file is read on each request so users can be changed live.
+// Successful checks are cached as bcrypt is slow by design.
+type webAuthHandler struct {
+	location string
+	handler  http.Handler
+
+	mu    sync.Mutex
+	valid map[string]bool
+}
+
+// authenticate checks the password of a user against its bcrypt hash
+func (h *webAuthHandler) authenticate(users map[string]string, user, password string) bool {
+	hashedPassword, known := users[user]
+	if !known {
+		hashedPassword = dummyHashedPassword
+	}
+
+	// the hash is part of the key so a changed password is checked again
+	sum := sha256.Sum256([]byte(user + ":" + hashedPassword + ":" + password))
+	key := hex.EncodeToString(sum[:])
+
+	h.mu.Lock()
+	valid := h.valid[key]
+	h.mu.Unlock()
+	if valid {
+		return true
+	}
+
+	err := bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
+	if err != nil || !known {
+		return false
+	}
+
+	h.mu.Lock()
+	if h.valid == nil {
+		h.valid = make(map[string]bool)
+	}
+	h.valid[key] = true
+	h.mu.Unlock()
+	return true
+}
+
+func (h *webAuthHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c, err := loadWebConfig(h.location)
+	if err != nil {
+		log.Printf("An error occured with the web configuration: %v", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	if len(c.Users) > 0 {
+		user, password, ok := r.BasicAuth()
+		if !ok || !h.authenticate(c.Users, user, password) {
+			w.Header().Set("WWW-Authenticate", "Basic")
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+	}
+
+	h.handler.ServeHTTP(w, r)
+}
+
+// listenAndServe serves the default mux, over TLS and behind basic auth
+// when a web configuration file is given. Certificates are reloaded on
+// each new connection.
+func listenAndServe(listen, webConfigFile string) error {
+	if webConfigFile == "" {
+		return http.ListenAndServe(listen, nil)
+	}
+
+	c, err := loadWebConfig(webConfigFile)
+	if err != nil {
+		return err
+	}
+	config, err := c.tlsConfig()
+	if err != nil {
+		return err
+	}
+
+	server := &http.Server{
+		Addr:    listen,
+		Handler: &webAuthHandler{location: webConfigFile, handler: http.DefaultServeMux},
+	}
+	if config == nil {
+		return server.ListenAndServe()
+	}
+
+	server.TLSConfig = &tls.Config{
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			c, err := loadWebConfig(webConfigFile)
+			if err != nil {
+				return nil, err
+			}
+			config, err := c.tlsConfig()
+			if err != nil {
+				return nil, err
+			}
+			if config == nil {
+				return nil, errors.New("TLS can not be disabled without a restart")
+			}
+			return config, nil
+		},
+	}
+	return server.ListenAndServeTLS("", "")
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestWebConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "web")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	location := filepath.Join(dir, "web.yml")
+	err = ioutil.WriteFile(location, []byte("basic_auth_users:\n  alice: "+string(hashedPassword)+"\n"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	handler := &webAuthHandler{location: location, handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})}
+	for _, tc := range []struct {
+		user, password string
+		expected       int
+	}{
+		{"alice", "secret", http.StatusOK},
+		{"alice", "wrong", http.StatusUnauthorized},
+		{"bob", "secret", http.StatusUnauthorized},
+		{"", "", http.StatusUnauthorized},
+	} {
+		r := httptest.NewRequest("GET", "/metrics", nil)
+		if tc.user != "" {
+			r.SetBasicAuth(tc.user, tc.password)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		if w.Code != tc.expected {
+			t.Error("Expected", tc.expected, "but got", w.Code)
+		}
+	}
+
+	if len(handler.valid) != 1 {
+		t.Error("Expected 1 cached check, but got", len(handler.valid))
+	}
+	if handler.authenticate(map[string]string{"alice": dummyHashedPassword}, "alice", "secret") {
+		t.Error("Expected a changed password not to use the cache")
+	}
+	if handler.authenticate(map[string]string{}, "bob", "dummy") {
+		t.Error("Expected an unknown user to be rejected")
+	}
+
+	c, err := loadWebConfig(location)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config, err := c.tlsConfig(); config != nil || err != nil {
+		t.Error("Expected TLS to be disabled, but got", config, err)
+	}
+
+	err = ioutil.WriteFile(location, []byte("tls_server_config:\n  cert_file: server.crt\n"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadWebConfig(location); err == nil {
+		t.Error("Expected an error without key_file")
+	}
+
+	err = ioutil.WriteFile(location, []byte("tls_server_config:\n  client_auth_type: Always\n"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadWebConfig(location); err == nil {
+		t.Error("Expected an error on an invalid client_auth_type")
+	}
+
+	err = ioutil.WriteFile(location, []byte("tls_server_config:\n  client_auth_type: RequireAndVerifyClientCert\n"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadWebConfig(location); err == nil {
+		t.Error("Expected an error without client_ca_file")
+	}
+}